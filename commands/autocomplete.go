@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/services"
+)
+
+const (
+	// maxChoices is the most suggestions Discord will show.
+	maxChoices = 25
+	// choiceLimit is the longest name or value Discord allows on a suggestion.
+	choiceLimit = 100
+	// minSuggestLength is how much needs to be typed before searching YouTube.
+	minSuggestLength = 3
+	// suggestDebounce is how long to wait for the user to stop typing before searching.
+	suggestDebounce = 300 * time.Millisecond
+	// suggestTimeout leaves room within Discord's 3 second window to respond
+	// once the debounce has passed. Slower searches still fill the cache.
+	suggestTimeout = 2 * time.Second
+)
+
+var (
+	// suggestionCache stops every keystroke running its own yt-dlp search.
+	suggestionCache = newSuggestionCache(context.Background())
+
+	typingMutex sync.Mutex
+	typingSeq   uint64
+	// Map of user IDs to their most recent autocomplete request.
+	typing = make(map[string]uint64)
+)
+
+// SetContext sets the context shared searches run under, cancel it on shutdown.
+func SetContext(ctx context.Context) {
+	suggestionCache = newSuggestionCache(ctx)
+}
+
+// newSuggestionCache creates the cache autocomplete searches YouTube through
+func newSuggestionCache(ctx context.Context) *services.SearchCache {
+	return services.NewSearchCache(ctx, func(ctx context.Context, query string) ([]services.YoutubeResult, error) {
+		youtubeService := &services.YoutubeService{}
+		return youtubeService.SearchYoutubeResults(ctx, query, searchResultLimit)
+	}, 10*time.Minute, 200)
+}
+
+// playAutocomplete suggests songs from the guild's history and YouTube as the user types a query.
+func playAutocomplete(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	query := strings.TrimSpace(focusedOptionValue(i))
+
+	// URLs are played as they are, there's nothing to suggest
+	if isValidURL(query) {
+		respondChoices(s, i, nil)
+		return
+	}
+
+	choices := newChoiceList()
+	// Guilds that haven't played anything yet only get search suggestions
+	if session, ok := discord.GetSession(i.GuildID); ok {
+		for _, entry := range session.Player.History() {
+			if query == "" || strings.Contains(strings.ToLower(entry.Track.Title), strings.ToLower(query)) {
+				choices.add("🕘 "+entry.Track.Title, entry.Track.URL)
+			}
+		}
+	}
+
+	if len([]rune(query)) >= minSuggestLength {
+		results, ok := suggestionCache.Cached(query)
+		if !ok && latestKeystroke(interactionUserID(i)) {
+			var err error
+			results, err = suggestionCache.Search(ctx, query, suggestTimeout)
+			if err != nil {
+				log.Printf("No search suggestions for %q: %v", query, err)
+			}
+		}
+		for _, result := range results {
+			choices.add(fmt.Sprintf("%s (%s)", result.Title, result.Duration), result.URL)
+		}
+	}
+
+	respondChoices(s, i, choices.choices)
+}
+
+// latestKeystroke waits briefly and reports whether this is still the user's most
+// recent autocomplete request, so only the last one while typing runs a search.
+func latestKeystroke(userID string) bool {
+	typingMutex.Lock()
+	typingSeq++
+	seq := typingSeq
+	typing[userID] = seq
+	typingMutex.Unlock()
+
+	time.Sleep(suggestDebounce)
+
+	typingMutex.Lock()
+	defer typingMutex.Unlock()
+	if typing[userID] != seq {
+		return false
+	}
+	delete(typing, userID)
+	return true
+}
+
+// choiceList collects suggestions, skipping duplicates and stopping at Discord's limit.
+type choiceList struct {
+	choices []*discordgo.ApplicationCommandOptionChoice
+	seen    map[string]bool
+}
+
+func newChoiceList() *choiceList {
+	return &choiceList{seen: make(map[string]bool)}
+}
+
+func (c *choiceList) add(name string, value string) {
+	if len(c.choices) >= maxChoices || value == "" || len(value) > choiceLimit || c.seen[value] {
+		return
+	}
+	c.seen[value] = true
+	c.choices = append(c.choices, &discordgo.ApplicationCommandOptionChoice{
+		Name:  truncate(name, choiceLimit),
+		Value: value,
+	})
+}
+
+// respondChoices sends the suggestions, Discord expects an empty list rather than none
+func respondChoices(s *discordgo.Session, i *discordgo.InteractionCreate, choices []*discordgo.ApplicationCommandOptionChoice) {
+	if choices == nil {
+		choices = []*discordgo.ApplicationCommandOptionChoice{}
+	}
+	if err := discord.AutocompleteRespond(s, i.Interaction, choices); err != nil {
+		log.Printf("Error sending autocomplete choices: %v", err)
+	}
+}
+
+// focusedOptionValue returns what the user has typed so far in the option being autocompleted.
+func focusedOptionValue(i *discordgo.InteractionCreate) string {
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Focused {
+			return opt.StringValue()
+		}
+	}
+	return ""
+}
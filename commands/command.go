@@ -1,21 +1,80 @@
 package commands
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+)
 
 // A map of all the registered commands, keyed by command name.
 var Commands = make(map[string]Command)
 
+// A map of all the registered message component handlers, keyed by the
+// prefix of the component's custom ID e.g. "search" for "search:1234".
+var Components = make(map[string]Component)
+
+// HandlerFunc handles an interaction. ctx is cancelled once the interaction has
+// been dealt with or the bot is shutting down, stopping any work started for it.
+type HandlerFunc func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate)
+
+// ComponentHandler runs when a user interacts with a message component such as a select menu.
+type ComponentHandler = HandlerFunc
+
+// Component holds the handler for a message component and who may use it.
+type Component struct {
+	Handler ComponentHandler
+	// Who may use the component, checked before the handler runs.
+	Permission Permission
+	// Optional check letting members without permission act on their own songs.
+	OwnSong OwnSongCheck
+}
+
+// FindComponent looks up the handler for a component's custom ID.
+func FindComponent(customID string) (Component, bool) {
+	component, ok := Components[discord.ComponentPrefix(customID)]
+	return component, ok
+}
+
 // Command holds the definition and handler for a slash commands.
 type Command struct {
 	// Data sent to Discord to register the command.
 	Definition *discordgo.ApplicationCommand
 	// Function that runs when the command is used.
-	Handler func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Handler HandlerFunc
+	// Optional function that suggests values for options with Autocomplete set.
+	Autocomplete HandlerFunc
+	// Who may use the command, checked before the handler runs.
+	Permission Permission
+	// Optional check letting members without permission act on their own songs.
+	OwnSong OwnSongCheck
+}
+
+// interactionUserID returns the ID of the user who triggered the interaction.
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// interactionUserName returns the display name of the user who triggered the interaction.
+func interactionUserName(i *discordgo.InteractionCreate) string {
+	if i.Member != nil {
+		return i.Member.DisplayName()
+	}
+	if i.User != nil {
+		return i.User.DisplayName()
+	}
+	return ""
 }
 
 type CommandRequest struct {
     CommandType string // "play" or "playlist"
     Interaction *discordgo.InteractionCreate
     Session     *discordgo.Session
-    Handler     func(*discordgo.Session, *discordgo.InteractionCreate)
+    Handler     HandlerFunc
 }
\ No newline at end of file
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/player"
+)
+
+// controlsHandler runs the player action for a button on the "Playing!" embed
+// and updates the embed in place to show the new state.
+func controlsHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	state := discord.ComponentState(i.MessageComponentData().CustomID)
+	if len(state) == 0 || i.Message == nil || len(i.Message.Embeds) == 0 {
+		return
+	}
+	action := state[0]
+	embed := i.Message.Embeds[0]
+	name := interactionUserName(i)
+
+	// Buttons on older songs' embeds no longer control anything
+	current := session.Player.CurrentSong()
+	if current == nil || current.URL != embed.URL {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "Finished playing."}
+		updateControls(session, i, embed, nil)
+		return
+	}
+
+	var note string
+	switch action {
+	case discord.ControlPause:
+		if session.Player.Pause() {
+			note = fmt.Sprintf("⏸️ Paused by %s", name)
+		} else if session.Player.Resume() {
+			note = fmt.Sprintf("▶️ Resumed by %s", name)
+		}
+	case discord.ControlSkip:
+		if !canSkip(session, i) {
+			vote, err := castSkipVote(session, i)
+			if err != nil {
+				session.InteractionRespondEphemeral(i.Interaction, voteMessage(err))
+				return
+			}
+			if vote.AlreadyVoted && !vote.Skipped {
+				session.InteractionRespondEphemeral(i.Interaction, fmt.Sprintf("You've already voted to skip (%d/%d).", vote.Votes, vote.Needed))
+				return
+			}
+			if vote.Skipped {
+				embed.Footer = &discordgo.MessageEmbedFooter{Text: "⏭️ Skipped by vote"}
+				updateControls(session, i, embed, nil)
+				return
+			}
+			discord.SetSkipVotes(embed, vote)
+			note = fmt.Sprintf("🗳️ %s voted to skip", name)
+			break
+		}
+		session.Player.Skip()
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("⏭️ Skipped by %s", name)}
+		updateControls(session, i, embed, nil)
+		return
+	case discord.ControlStop:
+		session.Player.Stop()
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("⏹️ Stopped by %s", name)}
+		updateControls(session, i, embed, nil)
+		return
+	case discord.ControlLoop:
+		mode := session.Player.GetLoopMode().Next()
+		session.Player.SetLoopMode(mode)
+		note = fmt.Sprintf("🔁 Loop set to %s by %s", mode, name)
+	case discord.ControlShuffle:
+		if session.Queue.Size() < 2 {
+			note = "🔀 Not enough songs in the queue to shuffle"
+		} else {
+			moved := session.Queue.Shuffle()
+			note = fmt.Sprintf("🔀 %s shuffled the queue, %d songs moved", name, moved)
+		}
+	default:
+		log.Printf("Unknown player control: %s", action)
+		return
+	}
+
+	paused := session.Player.IsPlayerPaused()
+	footer := player.NowPlayingFooter(session.Player.GetLoopMode())
+	if paused {
+		footer = "Paused"
+	}
+	if note != "" {
+		footer += " • " + note
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: footer}
+
+	updateControls(session, i, embed, discord.NowPlayingControls(paused, session.Player.GetLoopMode()))
+}
+
+// updateControls edits the "Playing!" embed in place, components are removed when nil
+func updateControls(session *discord.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) {
+	if err := session.InteractionUpdateEmbed(i.Interaction, embed, components); err != nil {
+		log.Printf("Error updating player controls: %v", err)
+	}
+}
+
+// pressesSkip lets everyone press skip, members without the DJ role vote to skip
+// songs they didn't request
+func pressesSkip(session *discord.Session, i *discordgo.InteractionCreate) bool {
+	state := discord.ComponentState(i.MessageComponentData().CustomID)
+	return len(state) > 0 && state[0] == discord.ControlSkip
+}
+
+func init() {
+	Components[discord.ControlsComponent] = Component{
+		Handler:    controlsHandler,
+		Permission: PermissionDJ,
+		OwnSong:    pressesSkip,
+	}
+}
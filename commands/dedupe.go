@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+)
+
+func dedupeHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	if session.Queue.IsEmpty() {
+		session.InteractionRespond(i.Interaction, "The queue is empty.")
+		return
+	}
+
+	removed := session.Queue.RemoveDuplicates()
+	if removed == 0 {
+		session.InteractionRespond(i.Interaction, "No duplicate songs in the queue.")
+		return
+	}
+
+	session.InteractionRespond(i.Interaction, fmt.Sprintf("🧹 Removed %d duplicate song(s) from the queue.", removed))
+}
+
+func init() {
+	Commands["dedupe"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "dedupe",
+			Description: "Removes duplicate songs from the queue.",
+		},
+		Handler:    dedupeHandler,
+		Permission: PermissionDJ,
+	}
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+)
+
+func historyHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	entries := session.Player.History()
+	if len(entries) == 0 {
+		session.InteractionRespond(i.Interaction, "Nothing has been played yet.")
+		return
+	}
+
+	page := 1
+	if options := i.ApplicationCommandData().Options; len(options) > 0 {
+		page = int(options[0].IntValue())
+	}
+
+	start, end, page, totalPages := paginate(len(entries), page)
+	embed := discord.HistoryEmbed(entries[start:end], start, page, totalPages)
+	if err := session.InteractionRespondEmbed(i.Interaction, embed); err != nil {
+		log.Printf("Error sending history embed: %v", err)
+	}
+}
+
+func init() {
+	Commands["history"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "history",
+			Description: "Shows the recently played songs.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "page",
+					Description: "The page of the history to view.",
+					Required:    false,
+				},
+			},
+		},
+		Handler: historyHandler,
+	}
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+)
+
+func joinHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	channelID := session.UserVoiceChannelID(interactionUserID(i))
+	if channelID == "" {
+		session.InteractionRespond(i.Interaction, "You need to be in a voice channel.")
+		return
+	}
+
+	if !session.IsVoiceConnected() {
+		if err := session.JoinVoiceChannel(i); err != nil {
+			log.Printf("Error joining voice channel: %v", err)
+			session.InteractionRespond(i.Interaction, "Sorry, I couldn't join your voice channel.")
+			return
+		}
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("🔊 Joined <#%s>.", channelID))
+		return
+	}
+
+	if session.GetVoiceChannelID() == channelID {
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("I'm already in <#%s>.", channelID))
+		return
+	}
+
+	// Move rather than rejoin so the current song carries on
+	if err := session.MoveToChannel(channelID); err != nil {
+		log.Printf("Error moving to voice channel: %v", err)
+		session.InteractionRespond(i.Interaction, "Sorry, I couldn't move to your voice channel.")
+		return
+	}
+	session.InteractionRespond(i.Interaction, fmt.Sprintf("🔊 Moved to <#%s>.", channelID))
+}
+
+func init() {
+	Commands["join"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "join",
+			Description: "Brings the bot to your voice channel, the music carries on.",
+		},
+		Handler:    joinHandler,
+		Permission: PermissionDJ,
+	}
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/player"
+)
+
+func loopHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	mode, err := player.ParseLoopMode(i.ApplicationCommandData().Options[0].StringValue())
+	if err != nil {
+		session.InteractionRespond(i.Interaction, "❌ Pick one of off, track or queue.")
+		return
+	}
+
+	session.Player.SetLoopMode(mode)
+
+	switch mode {
+	case player.LoopTrack:
+		session.InteractionRespond(i.Interaction, "🔂 Looping the current song.")
+	case player.LoopQueue:
+		session.InteractionRespond(i.Interaction, "🔁 Looping the queue.")
+	default:
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("Loop mode set to **%s**.", mode))
+	}
+}
+
+func init() {
+	Commands["loop"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "loop",
+			Description: "Loops the current song or the whole queue.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "mode",
+					Description: "What to loop.",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Off", Value: player.LoopOff.String()},
+						{Name: "Track", Value: player.LoopTrack.String()},
+						{Name: "Queue", Value: player.LoopQueue.String()},
+					},
+				},
+			},
+		},
+		Handler:    loopHandler,
+		Permission: PermissionDJ,
+	}
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+)
+
+func moveHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	songs := session.Queue.GetSongs()
+	if len(songs) == 0 {
+		session.InteractionRespond(i.Interaction, "The queue is empty. Nothing to move.")
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	from := int(optionMap["from"].IntValue())
+	to := int(optionMap["to"].IntValue())
+	if from > len(songs) || to > len(songs) {
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("❌ Invalid position. Please specify positions between 1 and %d", len(songs)))
+		return
+	}
+
+	// Positions are 1-indexed for users
+	if !session.Queue.Move(from-1, to-1) {
+		session.InteractionRespond(i.Interaction, "❌ Could not move that song, the queue changed. Check /showqueue and try again.")
+		return
+	}
+
+	session.InteractionRespond(i.Interaction, fmt.Sprintf("✅ Moved **%s** to position %d.", songs[from-1].Title, to))
+}
+
+func init() {
+	Commands["move"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "move",
+			Description: "Move a song to a different position in the queue.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "from",
+					Description: "The position of the song to move (1-indexed, use /showqueue to see positions).",
+					Required:    true,
+					MinValue:    func() *float64 { v := 1.0; return &v }(),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "to",
+					Description: "The position to move the song to.",
+					Required:    true,
+					MinValue:    func() *float64 { v := 1.0; return &v }(),
+				},
+			},
+		},
+		Handler:    moveHandler,
+		Permission: PermissionDJ,
+	}
+}
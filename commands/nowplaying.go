@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/player"
+)
+
+func nowplayingHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	song := session.Player.CurrentSong()
+	if song == nil {
+		session.InteractionRespond(i.Interaction, "Nothing is playing.")
+		return
+	}
+
+	needed := player.VotesNeeded(len(session.VoiceListeners()), discord.GetSettings(i.GuildID).VoteSkipPercent)
+	embed := discord.NowPlayingEmbed(discord.NowPlaying{
+		Song:     song,
+		Elapsed:  session.Player.Position(),
+		Paused:   session.Player.IsPlayerPaused(),
+		LoopMode: session.Player.GetLoopMode().String(),
+		Volume:   session.Player.GetVolume(),
+		SkipVote: player.SkipVote{Votes: session.Player.SkipVotes(), Needed: needed},
+	})
+
+	err := session.InteractionRespondEmbed(i.Interaction, embed)
+	if err != nil {
+		log.Printf("Error sending now playing embed: %v", err)
+	}
+}
+
+func init() {
+	Commands["nowplaying"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "nowplaying",
+			Description: "Shows the current song and how far into it we are.",
+		},
+		Handler: nowplayingHandler,
+	}
+}
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"context"
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+)
+
+func pauseHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+	if session.Player.Pause() {
+		session.InteractionRespond(i.Interaction, "⏸️ Paused the current song. Use /resume to carry on.")
+	} else if session.Player.IsPlayerPaused() {
+		session.InteractionRespond(i.Interaction, "The player is already paused.")
+	} else {
+		session.InteractionRespond(i.Interaction, "Nothing to pause.")
+	}
+}
+
+func init() {
+	Commands["pause"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "pause",
+			Description: "Pauses the current song.",
+		},
+		Handler:    pauseHandler,
+		Permission: PermissionDJ,
+	}
+}
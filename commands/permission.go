@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/queue"
+)
+
+// Permission is who may use a command or component.
+type Permission int
+
+const (
+	// PermissionEveryone lets any member use it, this is the default.
+	PermissionEveryone Permission = iota
+	// PermissionDJ limits it to members with the guild's DJ role and admins.
+	PermissionDJ
+)
+
+// OwnSongCheck reports whether an interaction only touches songs the member
+// requested, letting members without the DJ role skip or remove their own songs.
+type OwnSongCheck func(session *discord.Session, i *discordgo.InteractionCreate) bool
+
+// djBypass are the permissions that count as having the DJ role.
+const djBypass = discordgo.PermissionAdministrator | discordgo.PermissionManageServer
+
+// Guard wraps a handler so it only runs for members allowed to use it,
+// everyone else gets a private reply explaining why.
+func Guard(permission Permission, ownSong OwnSongCheck, handler HandlerFunc) HandlerFunc {
+	if permission == PermissionEveryone {
+		return handler
+	}
+
+	return func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		settings := discord.GetSettings(i.GuildID)
+		if isDJ(i.Member, settings.DJRoleID) {
+			handler(ctx, s, i)
+			return
+		}
+
+		session := discord.GetOrCreateSession(s, i)
+		if ownSong != nil && ownSong(session, i) {
+			handler(ctx, s, i)
+			return
+		}
+
+		message := fmt.Sprintf("🚫 You need the <@&%s> role to do that.", settings.DJRoleID)
+		if ownSong != nil {
+			message += " You can still skip or remove songs you requested."
+		}
+		session.InteractionRespondEphemeral(i.Interaction, message)
+	}
+}
+
+// isDJ reports whether a member may control playback for everyone. Guilds
+// without a DJ role leave playback open to all members.
+func isDJ(member *discordgo.Member, djRoleID string) bool {
+	if djRoleID == "" {
+		return true
+	}
+	if member == nil {
+		return false
+	}
+	if member.Permissions&djBypass != 0 {
+		return true
+	}
+	return slices.Contains(member.Roles, djRoleID)
+}
+
+// requestedCurrentSong reports whether the member requested the song that is playing.
+func requestedCurrentSong(session *discord.Session, i *discordgo.InteractionCreate) bool {
+	current := session.Player.CurrentSong()
+	return current != nil && requestedBy(current, i)
+}
+
+// requestedBy reports whether the member who sent the interaction requested the track.
+func requestedBy(track *queue.Track, i *discordgo.InteractionCreate) bool {
+	return track.RequesterID != "" && track.RequesterID == interactionUserID(i)
+}
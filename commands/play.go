@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/url"
@@ -11,7 +13,19 @@ import (
 	"github.com/coreyo-git/beatgopher/services"
 )
 
-func playHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+// searchTimeout is how long yt-dlp gets to look something up before it's killed.
+const searchTimeout = 30 * time.Second
+
+func playHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	searchAndQueue(ctx, s, i, func(session *discord.Session, song *services.YoutubeResult) error {
+		return session.Player.AddSong(i, song)
+	})
+}
+
+// searchAndQueue looks up the song from the query option, joins the user's voice
+// channel and hands the song to queueSong to be added to the queue. Songs the
+// guild's limits don't allow are turned down with the reason.
+func searchAndQueue(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, queueSong func(session *discord.Session, song *services.YoutubeResult) error) {
 	session := discord.GetOrCreateSession(s, i)
 
 	var query string
@@ -37,36 +51,31 @@ func playHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		log.Printf("Error responding to interaction: %v", err)
 	}
 
-	resultCh := make(chan services.YoutubeResult, 1)
-	errCh := make (chan error, 1)
+	// yt-dlp is killed if the search takes too long or the bot shuts down
+	ctx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
 
 	log.Printf("Received song request for: %v", query)
-	go func() {
-		// Handles the search and gets the piped out audio stream
-		song, err := handleSearch(session, i, query)
-		if err != nil {
-			errCh <- err
-			return
-		}
-		resultCh <- song
-	}()
-
-	select {
-	case song := <-resultCh:
-		log.Printf("Adding song: %v", song.Title)
-		err := session.JoinIfVoiceIsNotConnected(i)
-		if err != nil {
-			log.Printf("Error joining voice channel for guild: %v when using /play", i.GuildID)
-		}
-		session.Player.AddSong(i, &song)
+	song, err := handleSearch(ctx, query)
 
+	if err != nil {
+		reportSearchError(ctx, session, i, err, "Sorry I couldn't find that song or process the URL.")
+		return
+	}
 
-	case err := <-errCh:
-		log.Printf("Search Error: %v", err)
-		session.FollowupMessage(i.Interaction, "Sorry I couldn't find that song or process the URL.")
-	case <- time.After(30 * time.Second):
-		log.Printf("Search timeout for query: %s", query)
-		session.FollowupMessage(i.Interaction, "Search timed out. Please try again.")
+	// Check before joining so a rejected song doesn't pull the bot into voice
+	if err := session.Player.CheckLimits(i, &song); err != nil {
+		session.FollowupMessage(i.Interaction, limitMessage(err))
+		return
+	}
+
+	log.Printf("Adding song: %v", song.Title)
+	err = session.JoinIfVoiceIsNotConnected(i)
+	if err != nil {
+		log.Printf("Error joining voice channel for guild: %v when using /%s", i.GuildID, i.ApplicationCommandData().Name)
+	}
+	if err := queueSong(session, &song); err != nil {
+		session.FollowupMessage(i.Interaction, limitMessage(err))
 	}
 }
 
@@ -81,38 +90,59 @@ func init() {
 					Type:        discordgo.ApplicationCommandOptionString,
 					Name:        "query",
 					Description: "The URL of the song or a search term.",
-					Required:    true,
-					MinLength:   &minLength,
+					Required:     true,
+					MinLength:    &minLength,
+					Autocomplete: true,
 				},
 			},
 		},
-		Handler: playHandler,
+		Handler:      playHandler,
+		Autocomplete: playAutocomplete,
 	}
 }
 
 // called when the user's query is a song name
-func handleSearch(ds *discord.Session, i *discordgo.InteractionCreate, query string) (services.YoutubeResult, error) {
+func handleSearch(ctx context.Context, query string) (services.YoutubeResult, error) {
 	youtubeService := &services.YoutubeService{}
 
 	if isValidURL(query) {
-		result, err := youtubeService.GetYoutubeInfo(query)
+		result, err := youtubeService.GetYoutubeInfo(ctx, query)
 		if (err) != nil {
 			return services.YoutubeResult{}, err
 		}
 		return result, nil
 	}
 
-	result, err := youtubeService.SearchYoutube(query)
+	result, err := youtubeService.SearchYoutube(ctx, query)
 
 	if err != nil {
 		log.Printf("Error handling search: %v", err)
-		ds.FollowupMessage(i.Interaction, "Sorry, I couldn't find that song or process the URL.")
 		return services.YoutubeResult{}, err
 	}
 
 	return result, nil
 }
 
+// reportSearchError lets the user know a yt-dlp lookup failed, sending notFound
+// unless it timed out. Nothing is sent if the bot is shutting down.
+func reportSearchError(ctx context.Context, session *discord.Session, i *discordgo.InteractionCreate, err error, notFound string) {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		log.Printf("Search timed out: %v", err)
+		session.FollowupMessage(i.Interaction, "Search timed out. Please try again.")
+	case ctx.Err() != nil:
+		log.Printf("Search cancelled: %v", err)
+	default:
+		log.Printf("Search Error: %v", err)
+		session.FollowupMessage(i.Interaction, notFound)
+	}
+}
+
+// limitMessage turns a song the guild's limits rejected into a reply for the user
+func limitMessage(err error) string {
+	return fmt.Sprintf("🚫 Sorry, %s.", err)
+}
+
 // checks if a string is a valid URL.
 func isValidURL(s string) bool {
 	_, err := url.ParseRequestURI(s)
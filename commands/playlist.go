@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/url"
@@ -11,7 +12,7 @@ import (
 	"github.com/coreyo-git/beatgopher/services"
 )
 
-func playlistHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func playlistHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
 	session := discord.GetOrCreateSession(s, i)
 
 	var query string
@@ -48,11 +49,25 @@ func playlistHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 
 	// Handles the search and gets the piped out audio stream
-	songs, err := handlePlaylist(session, i, query, total, random)
+	// yt-dlp is killed if the playlist takes too long to load or the bot shuts down
+	ctx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
+	songs, err := handlePlaylist(ctx, query, total, random)
 
 	if err != nil {
+		reportSearchError(ctx, session, i, err, "Sorry, I couldn't load that playlist.")
 		return
-	} 
+	}
+	if len(songs) == 0 {
+		session.FollowupMessage(i.Interaction, "Sorry, I couldn't find any songs in that playlist.")
+		return
+	}
+
+	// Check before joining so a playlist the limits turn away doesn't pull the bot into voice
+	if err := firstAllowed(session, i, songs); err != nil {
+		session.FollowupMessage(i.Interaction, limitMessage(err))
+		return
+	}
 
 	err = session.JoinIfVoiceIsNotConnected(i)
 	if err != nil {
@@ -60,7 +75,28 @@ func playlistHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 
 	log.Println("Adding songs from playlist")
-	session.Player.AddSongs(i, songs)
+	added, err := session.Player.AddSongs(i, songs)
+	if added == 0 {
+		session.FollowupMessage(i.Interaction, limitMessage(err))
+	} else if err != nil {
+		session.FollowupMessage(i.Interaction, fmt.Sprintf("🚫 Added %d of %d songs, %s.", added, len(songs), err))
+	}
+}
+
+// firstAllowed returns nil if the limits let the user queue at least one of songs,
+// otherwise the reason the first song was turned away.
+func firstAllowed(session *discord.Session, i *discordgo.InteractionCreate, songs []services.YoutubeResult) error {
+	var firstErr error
+	for j := range songs {
+		err := session.Player.CheckLimits(i, &songs[j])
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
 }
 
 func init() {
@@ -92,9 +128,9 @@ func init() {
 }
 
 // called when the user's query is a song name
-func handlePlaylist(d *discord.Session, i *discordgo.InteractionCreate, q string, total int64, random bool) ([]services.YoutubeResult, error) {
+func handlePlaylist(ctx context.Context, q string, total int64, random bool) ([]services.YoutubeResult, error) {
 	if isValidPlaylistURL(q){
-		results, err := services.GetYoutubePlaylistInfo(q, total, random)
+		results, err := services.GetYoutubePlaylistInfo(ctx, q, total, random)
 		if(err) != nil {
 			return []services.YoutubeResult{}, err
 		}
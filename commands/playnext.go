@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/services"
+)
+
+func playnextHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	searchAndQueue(ctx, s, i, func(session *discord.Session, song *services.YoutubeResult) error {
+		return session.Player.PlayNext(i, song)
+	})
+}
+
+func init() {
+	minLength := 3
+	Commands["playnext"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "playnext",
+			Description: "Puts a song at the front of the queue",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "query",
+					Description: "The URL of the song or a search term.",
+					Required:    true,
+					MinLength:   &minLength,
+				},
+			},
+		},
+		Handler:    playnextHandler,
+		Permission: PermissionDJ,
+	}
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+)
+
+func previousHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+	if len(session.Player.History()) == 0 {
+		session.InteractionRespond(i.Interaction, "There's no previous song to go back to.")
+		return
+	}
+
+	// Playback may need to start again, so make sure we're in voice first
+	if !session.IsVoiceConnected() {
+		if err := session.JoinVoiceChannel(i); err != nil {
+			session.InteractionRespond(i.Interaction, "You need to be in a voice channel to go back a song.")
+			return
+		}
+	}
+
+	track, ok := session.Player.Previous()
+	if !ok {
+		session.InteractionRespond(i.Interaction, "There's no previous song to go back to.")
+		return
+	}
+
+	session.InteractionRespond(i.Interaction, fmt.Sprintf("⏮️ Going back to **%s**.", track.Title))
+}
+
+func init() {
+	Commands["previous"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "previous",
+			Description: "Plays the previous song again.",
+		},
+		Handler:    previousHandler,
+		Permission: PermissionDJ,
+	}
+}
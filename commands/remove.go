@@ -1,15 +1,17 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/coreyo-git/beatgopher/discord"
-	"github.com/coreyo-git/beatgopher/services"
+	"github.com/coreyo-git/beatgopher/queue"
 )
 
-func removeHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func removeHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
 	session := discord.GetOrCreateSession(s, i)
 
 	// Get the songs from the queue to check if it's empty
@@ -19,6 +21,27 @@ func removeHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
+	removedSong, err := findSongToRemove(i, songs)
+	if err != nil {
+		session.InteractionRespond(i.Interaction, err.Error())
+		return
+	}
+
+	// Members without the DJ role only got this far by having songs of their own
+	if !isDJ(i.Member, discord.GetSettings(i.GuildID).DJRoleID) && !requestedBy(removedSong, i) {
+		session.InteractionRespondEphemeral(i.Interaction, "🚫 You can only remove songs you requested.")
+		return
+	}
+
+	if session.RemoveFromQueue(removedSong) {
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("✅ Removed **%s** from the queue.%s", removedSong.Title, requestedByNote(removedSong)))
+	} else {
+		session.InteractionRespond(i.Interaction, "❌ Could not find the specified song to remove.")
+	}
+}
+
+// findSongToRemove picks the song the user asked to remove by position or search query
+func findSongToRemove(i *discordgo.InteractionCreate, songs []*queue.Track) (*queue.Track, error) {
 	// Get command options
 	options := i.ApplicationCommandData().Options
 	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
@@ -26,65 +49,56 @@ func removeHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		optionMap[opt.Name] = opt
 	}
 
-	var removedSong *string
-	var err error
-
 	// Check if user provided a position number
 	if positionOpt, exists := optionMap["position"]; exists {
-		position := int(positionOpt.IntValue())
-		removedSong, err = removeByPosition(session, position, songs)
-	} else if queryOpt, exists := optionMap["query"]; exists {
-		// Remove by search query (title or partial title match)
-		query := queryOpt.StringValue()
-		removedSong, err = removeByQuery(session, query, songs)
-	} else {
-		session.InteractionRespond(i.Interaction, "Please provide either a position number or a search query to remove a song.")
-		return
+		return findByPosition(int(positionOpt.IntValue()), songs)
 	}
-
-	if err != nil {
-		session.InteractionRespond(i.Interaction, err.Error())
-		return
-	}
-
-	if removedSong != nil {
-		session.InteractionRespond(i.Interaction, fmt.Sprintf("✅ Removed **%s** from the queue.", *removedSong))
-	} else {
-		session.InteractionRespond(i.Interaction, "❌ Could not find the specified song to remove.")
+	if queryOpt, exists := optionMap["query"]; exists {
+		// Remove by search query (title or partial title match)
+		return findByQuery(queryOpt.StringValue(), songs)
 	}
+	return nil, fmt.Errorf("Please provide either a position number or a search query to remove a song.")
 }
 
-// removeByPosition removes a song at the specified position (1-indexed)
-func removeByPosition(s discord.DiscordSessionInterface, position int, songs []*services.YoutubeResult) (*string, error) {
+// findByPosition finds the song at the specified position (1-indexed)
+func findByPosition(position int, songs []*queue.Track) (*queue.Track, error) {
 	if position < 1 || position > len(songs) {
 		return nil, fmt.Errorf("❌ Invalid position. Please specify a position between 1 and %d", len(songs))
 	}
 
 	// Convert to 0-indexed
-	songToRemove := songs[position-1]
-
-	if s.RemoveFromQueue(songToRemove) {
-		return &songToRemove.Title, nil
-	}
-
-	return nil, nil
+	return songs[position-1], nil
 }
 
-// removeByQuery removes the first song that matches the query (case-insensitive partial match)
-func removeByQuery(s discord.DiscordSessionInterface, query string, songs []*services.YoutubeResult) (*string, error) {
+// findByQuery finds the first song that matches the query (case-insensitive partial match)
+func findByQuery(query string, songs []*queue.Track) (*queue.Track, error) {
 	query = strings.ToLower(query)
 
 	for _, song := range songs {
 		if strings.Contains(strings.ToLower(song.Title), query) {
-			if s.RemoveFromQueue(song) {
-				return &song.Title, nil
-			}
+			return song, nil
 		}
 	}
 
 	return nil, fmt.Errorf("❌ No song found matching '%s'", query)
 }
 
+// hasOwnSong lets members with a song in the queue through to removeHandler,
+// which checks the song they picked is one of theirs
+func hasOwnSong(session *discord.Session, i *discordgo.InteractionCreate) bool {
+	return slices.ContainsFunc(session.Queue.GetSongs(), func(song *queue.Track) bool {
+		return requestedBy(song, i)
+	})
+}
+
+// requestedByNote mentions who requested a track, if we know
+func requestedByNote(track *queue.Track) string {
+	if track.RequesterName == "" {
+		return ""
+	}
+	return fmt.Sprintf(" It was requested by **%s**.", track.RequesterName)
+}
+
 func init() {
 	Commands["remove"] = Command{
 		Definition: &discordgo.ApplicationCommand{
@@ -107,6 +121,8 @@ func init() {
 				},
 			},
 		},
-		Handler: removeHandler,
+		Handler:    removeHandler,
+		Permission: PermissionDJ,
+		OwnSong:    hasOwnSong,
 	}
 }
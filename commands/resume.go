@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+)
+
+func resumeHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+	if session.Player.Resume() {
+		session.InteractionRespond(i.Interaction, "▶️ Resumed the current song.")
+		return
+	}
+
+	// Pick up a queue saved before the bot restarted
+	if !session.Player.IsPlayerPlaying() && discord.HasSavedSession(i.GuildID) {
+		session.InteractionRespond(i.Interaction, "Picking up where we left off...")
+
+		restored, err := discord.ResumeSavedSession(s, i)
+		if err != nil {
+			log.Printf("Error resuming saved queue for guild %s: %v", i.GuildID, err)
+			session.FollowupMessage(i.Interaction, "Sorry, I couldn't resume the saved queue.")
+			return
+		}
+
+		session.FollowupMessage(i.Interaction, fmt.Sprintf("▶️ Restored **%d** song(s).", restored))
+		return
+	}
+
+	session.InteractionRespond(i.Interaction, "Nothing to resume.")
+}
+
+func init() {
+	Commands["resume"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "resume",
+			Description: "Resumes the paused song, or the queue saved before a restart.",
+		},
+		Handler:    resumeHandler,
+		Permission: PermissionDJ,
+	}
+}
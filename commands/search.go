@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/services"
+)
+
+const (
+	// searchResultLimit is how many results /search offers to pick from.
+	searchResultLimit = 5
+	// searchExpiry is how long the results stay pickable before the menu stops working.
+	searchExpiry = 5 * time.Minute
+	// selectLabelLimit is the longest label Discord allows on a select menu option.
+	selectLabelLimit = 100
+)
+
+// pendingSearch holds the results of a /search until the user picks one.
+type pendingSearch struct {
+	userID  string
+	results []services.YoutubeResult
+}
+
+var (
+	pendingSearchesMutex sync.Mutex
+	// Map of search interaction IDs to the results shown in their select menu.
+	pendingSearches = make(map[string]pendingSearch)
+)
+
+func searchHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		session.InteractionRespond(i.Interaction, "Please provide something to search for.")
+		return
+	}
+	query := options[0].StringValue()
+
+	// Acknowledge command and reply to avoid timeout.
+	err := session.InteractionRespond(i.Interaction, fmt.Sprintf("Searching for `%s`...", query))
+	if err != nil {
+		log.Printf("Error responding to interaction: %v", err)
+		return
+	}
+
+	// yt-dlp is killed if the search takes too long or the bot shuts down
+	ctx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
+
+	youtubeService := &services.YoutubeService{}
+	results, err := youtubeService.SearchYoutubeResults(ctx, query, searchResultLimit)
+	if err != nil {
+		reportSearchError(ctx, session, i, err, "Sorry, I couldn't find anything for that search.")
+		return
+	}
+
+	pendingSearchesMutex.Lock()
+	pendingSearches[i.ID] = pendingSearch{userID: interactionUserID(i), results: results}
+	pendingSearchesMutex.Unlock()
+
+	// Forget the results once the menu has expired
+	time.AfterFunc(searchExpiry, func() {
+		takePendingSearch(i.ID)
+	})
+
+	menu := discordgo.SelectMenu{
+		CustomID:    discord.ComponentID(discord.SearchComponent, i.ID),
+		Placeholder: "Pick a song to add to the queue",
+		Options:     searchOptions(results),
+	}
+	err = session.FollowupComponents(i.Interaction, "Here's what I found:", []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{menu}},
+	})
+	if err != nil {
+		log.Printf("Error sending search results: %v", err)
+	}
+}
+
+// searchSelectHandler queues the song picked from a /search select menu.
+func searchSelectHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+	data := i.MessageComponentData()
+
+	state := discord.ComponentState(data.CustomID)
+	if len(state) == 0 || len(data.Values) == 0 {
+		return
+	}
+	searchID := state[0]
+
+	pendingSearchesMutex.Lock()
+	search, ok := pendingSearches[searchID]
+	pendingSearchesMutex.Unlock()
+	if !ok {
+		session.InteractionUpdate(i.Interaction, "This search has expired, use /search again.")
+		return
+	}
+
+	if search.userID != interactionUserID(i) {
+		session.InteractionRespondEphemeral(i.Interaction, "Only the person who searched can pick a song.")
+		return
+	}
+
+	index, err := strconv.Atoi(data.Values[0])
+	if err != nil || index < 0 || index >= len(search.results) {
+		log.Printf("Invalid search selection: %v", data.Values)
+		return
+	}
+
+	// Each search can only be picked from once
+	if _, ok := takePendingSearch(searchID); !ok {
+		return
+	}
+	song := search.results[index]
+
+	err = session.InteractionUpdate(i.Interaction, fmt.Sprintf("Picked **%s**.", song.Title))
+	if err != nil {
+		log.Printf("Error responding to interaction: %v", err)
+	}
+
+	// Check before joining so a rejected song doesn't pull the bot into voice
+	if err := session.Player.CheckLimits(i, &song); err != nil {
+		session.FollowupMessage(i.Interaction, limitMessage(err))
+		return
+	}
+
+	err = session.JoinIfVoiceIsNotConnected(i)
+	if err != nil {
+		log.Printf("Error joining voice channel for guild: %v when using /search", i.GuildID)
+	}
+
+	if err := session.Player.AddSong(i, &song); err != nil {
+		session.FollowupMessage(i.Interaction, limitMessage(err))
+	}
+}
+
+// takePendingSearch removes and returns the results of a search
+func takePendingSearch(searchID string) (pendingSearch, bool) {
+	pendingSearchesMutex.Lock()
+	defer pendingSearchesMutex.Unlock()
+
+	search, ok := pendingSearches[searchID]
+	delete(pendingSearches, searchID)
+	return search, ok
+}
+
+// searchOptions turns search results into select menu options, using the index as the value.
+func searchOptions(results []services.YoutubeResult) []discordgo.SelectMenuOption {
+	options := make([]discordgo.SelectMenuOption, 0, len(results))
+	for j, result := range results {
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       truncate(result.Title, selectLabelLimit),
+			Value:       strconv.Itoa(j),
+			Description: truncate(fmt.Sprintf("%s • %s", result.Channel, result.Duration), selectLabelLimit),
+		})
+	}
+	return options
+}
+
+// truncate shortens s to at most limit characters, marking that it was cut off.
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+func init() {
+	minLength := 3
+	Commands["search"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "search",
+			Description: "Searches YouTube and lets you pick which result to play.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "query",
+					Description: "What to search for.",
+					Required:    true,
+					MinLength:   &minLength,
+				},
+			},
+		},
+		Handler: searchHandler,
+	}
+	Components[discord.SearchComponent] = Component{Handler: searchSelectHandler}
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/services"
+)
+
+func seekHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	if !session.Player.IsPlayerPlaying() {
+		session.InteractionRespond(i.Interaction, "Nothing is playing.")
+		return
+	}
+
+	input := i.ApplicationCommandData().Options[0].StringValue()
+	position, err := parseSeekPosition(input, session.Player.Position())
+	if err != nil {
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	if err := session.Player.Seek(position); err != nil {
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("❌ Couldn't seek: %v", err))
+		return
+	}
+
+	session.InteractionRespond(i.Interaction, fmt.Sprintf("⏩ Seeked to `%s`.", services.FormatDuration(position)))
+}
+
+// parseSeekPosition turns user input into a position in the song.
+// Absolute positions look like "1:23" or "83", relative ones like "+30s" or "-1:00".
+func parseSeekPosition(input string, current time.Duration) (time.Duration, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return 0, fmt.Errorf("please provide a timestamp like `1:23` or `+30s`")
+	}
+
+	sign := 0
+	switch input[0] {
+	case '+':
+		sign = 1
+	case '-':
+		sign = -1
+	}
+	if sign != 0 {
+		input = input[1:]
+	}
+
+	offset, err := parseTimestamp(input)
+	if err != nil {
+		return 0, err
+	}
+
+	position := offset
+	if sign != 0 {
+		position = current + time.Duration(sign)*offset
+	}
+	if position < 0 {
+		position = 0
+	}
+
+	return position, nil
+}
+
+// parseTimestamp accepts clock style ("1:23"), plain seconds ("83") or Go style ("1m23s") timestamps.
+func parseTimestamp(s string) (time.Duration, error) {
+	if strings.Contains(s, ":") {
+		return services.ParseDuration(s)
+	}
+	if seconds, err := strconv.Atoi(s); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid timestamp `%s`, try something like `1:23` or `+30s`", s)
+}
+
+func init() {
+	Commands["seek"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "seek",
+			Description: "Jumps to a point in the current song.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "position",
+					Description: "A timestamp like 1:23, or a relative jump like +30s or -10s.",
+					Required:    true,
+				},
+			},
+		},
+		Handler:    seekHandler,
+		Permission: PermissionDJ,
+	}
+}
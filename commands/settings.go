@@ -0,0 +1,296 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/player"
+	"github.com/coreyo-git/beatgopher/services"
+	"github.com/coreyo-git/beatgopher/store"
+)
+
+// manageServer limits a command to members with the Manage Server permission.
+var manageServer int64 = discordgo.PermissionManageServer
+
+// settingNames lists the settings that /settings reset can put back individually.
+var settingNames = []string{
+	"default_volume",
+	"dj_role",
+	"announce_channel",
+	"idle_timeout",
+	"max_track_length",
+	"max_queue_size",
+	"max_tracks_per_user",
+	"loop_default",
+	"vote_skip_percent",
+}
+
+func settingsHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	// Discord hides the command from other members, but server admins can override that
+	if i.Member == nil || i.Member.Permissions&discordgo.PermissionManageServer == 0 {
+		session.InteractionRespondEphemeral(i.Interaction, "You need the Manage Server permission to change settings.")
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return
+	}
+	subcommand := options[0]
+
+	switch subcommand.Name {
+	case "view":
+		session.InteractionRespondEmbed(i.Interaction, discord.SettingsEmbed(discord.GetSettings(i.GuildID)))
+	case "set":
+		settingsSet(session, i, subcommand.Options)
+	case "reset":
+		settingsReset(session, i, subcommand.Options)
+	}
+}
+
+// settingsSet changes each setting given as an option
+func settingsSet(session *discord.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	if len(options) == 0 {
+		session.InteractionRespondEphemeral(i.Interaction, "Pick at least one setting to change.")
+		return
+	}
+
+	// Parse everything first so a bad value doesn't leave the settings half changed
+	changes := make([]func(settings *store.GuildSettings), 0, len(options))
+	for _, opt := range options {
+		change, err := settingChange(opt)
+		if err != nil {
+			session.InteractionRespondEphemeral(i.Interaction, fmt.Sprintf("Couldn't set %s: %v", opt.Name, err))
+			return
+		}
+		changes = append(changes, change)
+	}
+
+	settings, err := discord.UpdateSettings(i.GuildID, func(settings *store.GuildSettings) {
+		for _, change := range changes {
+			change(settings)
+		}
+	})
+	if err != nil {
+		log.Printf("Error updating settings for guild %s: %v", i.GuildID, err)
+		session.InteractionRespond(i.Interaction, "Sorry, I couldn't save the settings.")
+		return
+	}
+
+	session.InteractionRespondEmbed(i.Interaction, discord.SettingsEmbed(settings))
+}
+
+// settingChange reads the value of a /settings set option into a change to apply
+func settingChange(opt *discordgo.ApplicationCommandInteractionDataOption) (func(settings *store.GuildSettings), error) {
+	switch opt.Name {
+	case "default_volume":
+		volume := int(opt.IntValue())
+		return func(settings *store.GuildSettings) { settings.DefaultVolume = volume }, nil
+	case "dj_role":
+		roleID := fmt.Sprint(opt.Value)
+		return func(settings *store.GuildSettings) { settings.DJRoleID = roleID }, nil
+	case "announce_channel":
+		channelID := fmt.Sprint(opt.Value)
+		return func(settings *store.GuildSettings) { settings.AnnounceChannelID = channelID }, nil
+	case "idle_timeout":
+		timeout, err := parseSettingDuration(opt.StringValue())
+		if err != nil {
+			return nil, err
+		}
+		return func(settings *store.GuildSettings) { settings.IdleTimeout = timeout }, nil
+	case "max_track_length":
+		length, err := parseSettingDuration(opt.StringValue())
+		if err != nil {
+			return nil, err
+		}
+		return func(settings *store.GuildSettings) { settings.MaxTrackLength = length }, nil
+	case "max_queue_size":
+		size := int(opt.IntValue())
+		return func(settings *store.GuildSettings) { settings.MaxQueueSize = size }, nil
+	case "max_tracks_per_user":
+		count := int(opt.IntValue())
+		return func(settings *store.GuildSettings) { settings.MaxTracksPerUser = count }, nil
+	case "loop_default":
+		mode, err := player.ParseLoopMode(opt.StringValue())
+		if err != nil {
+			return nil, err
+		}
+		return func(settings *store.GuildSettings) { settings.LoopDefault = mode.String() }, nil
+	case "vote_skip_percent":
+		percent := int(opt.IntValue())
+		return func(settings *store.GuildSettings) { settings.VoteSkipPercent = percent }, nil
+	}
+	return nil, fmt.Errorf("unknown setting")
+}
+
+// settingsReset puts one setting, or all of them, back to the default
+func settingsReset(session *discord.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	var settings store.GuildSettings
+	var err error
+	if len(options) == 0 {
+		settings, err = discord.ResetSettings(i.GuildID)
+	} else {
+		name := options[0].StringValue()
+		defaults := discord.DefaultSettings()
+		settings, err = discord.UpdateSettings(i.GuildID, func(settings *store.GuildSettings) {
+			resetSetting(settings, defaults, name)
+		})
+	}
+	if err != nil {
+		log.Printf("Error resetting settings for guild %s: %v", i.GuildID, err)
+		session.InteractionRespond(i.Interaction, "Sorry, I couldn't reset the settings.")
+		return
+	}
+
+	session.InteractionRespondEmbed(i.Interaction, discord.SettingsEmbed(settings))
+}
+
+// resetSetting copies a single setting from the defaults
+func resetSetting(settings *store.GuildSettings, defaults store.GuildSettings, name string) {
+	switch name {
+	case "default_volume":
+		settings.DefaultVolume = defaults.DefaultVolume
+	case "dj_role":
+		settings.DJRoleID = defaults.DJRoleID
+	case "announce_channel":
+		settings.AnnounceChannelID = defaults.AnnounceChannelID
+	case "idle_timeout":
+		settings.IdleTimeout = defaults.IdleTimeout
+	case "max_track_length":
+		settings.MaxTrackLength = defaults.MaxTrackLength
+	case "max_queue_size":
+		settings.MaxQueueSize = defaults.MaxQueueSize
+	case "max_tracks_per_user":
+		settings.MaxTracksPerUser = defaults.MaxTracksPerUser
+	case "loop_default":
+		settings.LoopDefault = defaults.LoopDefault
+	case "vote_skip_percent":
+		settings.VoteSkipPercent = defaults.VoteSkipPercent
+	}
+}
+
+// parseSettingDuration reads a length of time as a timestamp (1:30:00) or a Go duration (90m), 0 turns it off
+func parseSettingDuration(input string) (time.Duration, error) {
+	input = strings.TrimSpace(input)
+	if input == "0" {
+		return 0, nil
+	}
+	if d, err := time.ParseDuration(input); err == nil && d >= 0 {
+		return d, nil
+	}
+	if d, err := services.ParseDuration(input); err == nil {
+		return d, nil
+	}
+	return 0, fmt.Errorf("give a time like `1:30:00` or `90m`, or `0` to turn it off")
+}
+
+func init() {
+	minValue := 0.0
+	maxVolume := float64(player.MaxVolume)
+	minPercent := 1.0
+
+	resetChoices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(settingNames))
+	for _, name := range settingNames {
+		resetChoices = append(resetChoices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+	}
+
+	Commands["settings"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:                     "settings",
+			Description:              "Shows or changes the bot's settings for this server.",
+			DefaultMemberPermissions: &manageServer,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "view",
+					Description: "Shows the current settings.",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "set",
+					Description: "Changes one or more settings.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "default_volume",
+							Description: "The volume the player starts at (0-200%).",
+							MinValue:    &minValue,
+							MaxValue:    maxVolume,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionRole,
+							Name:        "dj_role",
+							Description: "The role allowed to control playback.",
+						},
+						{
+							Type:         discordgo.ApplicationCommandOptionChannel,
+							Name:         "announce_channel",
+							Description:  "The channel songs are announced in.",
+							ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "idle_timeout",
+							Description: "How long to wait with nothing playing, or no one listening, before leaving e.g. 10m, 0 to stay.",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "max_track_length",
+							Description: "The longest song that can be queued e.g. 1:30:00 or 90m, 0 for no limit.",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "max_queue_size",
+							Description: "The most songs the queue can hold, 0 for no limit.",
+							MinValue:    &minValue,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "max_tracks_per_user",
+							Description: "The most songs one person can have in the queue, 0 for no limit.",
+							MinValue:    &minValue,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "loop_default",
+							Description: "The loop mode the player starts in.",
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "Off", Value: player.LoopOff.String()},
+								{Name: "Track", Value: player.LoopTrack.String()},
+								{Name: "Queue", Value: player.LoopQueue.String()},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "vote_skip_percent",
+							Description: "The share of listeners who must /voteskip to skip a song (1-100%).",
+							MinValue:    &minPercent,
+							MaxValue:    100,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "reset",
+					Description: "Puts a setting, or all of them, back to the default.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "setting",
+							Description: "The setting to reset, leave out to reset everything.",
+							Choices:     resetChoices,
+						},
+					},
+				},
+			},
+		},
+		Handler: settingsHandler,
+	}
+}
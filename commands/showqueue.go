@@ -1,17 +1,21 @@
 package commands
 
 import (
+	"context"
 	"log"
 	"math"
+	"strconv"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/queue"
 )
 
 // songsPerPage is the number of songs to display on each page of the queue.
 const songsPerPage = 10
 
-func showqueueHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func showqueueHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
 	// Create a new Discord session wrapper.
 	session := discord.GetOrCreateSession(s, i)
 
@@ -22,37 +26,78 @@ func showqueueHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	// Get the page number from the command options.
-	page := 1
-	if i.ApplicationCommandData().Options != nil && len(i.ApplicationCommandData().Options) > 0 {
-		page = int(i.ApplicationCommandData().Options[0].IntValue())
-	}
+	start, end, page, totalPages := paginate(len(songs), 1)
+	embed := discord.QueueEmbed(songs[start:end], start, page, totalPages, songs, queueWaits(session, songs))
 
-	// Calculate the total number of pages.
-	totalPages := int(math.Ceil(float64(len(songs)) / float64(songsPerPage)))
-	if page > totalPages {
-		page = totalPages
+	err := session.InteractionRespondEmbedComponents(i.Interaction, embed, discord.QueueControls(page, totalPages))
+	if err != nil {
+		log.Printf("Error sending queue embed: %v", err)
 	}
+}
 
-	// Calculate the start and end indices for the current page.
-	start := (page - 1) * songsPerPage
-	end := start + songsPerPage
-	if end > len(songs) {
-		end = len(songs)
-	}
+// queuePageHandler shows the page of the queue carried in the button's custom ID,
+// editing the queue embed in place.
+func queuePageHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
 
-	// Respond to the interaction to prevent time out.
-	err := session.InteractionRespond(i.Interaction, "Loading queue...")
+	state := discord.ComponentState(i.MessageComponentData().CustomID)
+	if len(state) == 0 {
+		return
+	}
+	page, err := strconv.Atoi(state[0])
 	if err != nil {
-		log.Printf("Error responding to interaction: %v", err)
+		log.Printf("Invalid queue page: %v", state)
 		return
 	}
 
-	err = session.SendQueueEmbed(songs[start:end], page, totalPages)
-	if err != nil {
-		session.FollowupMessage(i.Interaction, "Something went wrong while trying to show the queue.")
-		log.Printf("Error sending queue embed: %v", err)
+	// The queue may have changed since the embed was sent, so the page is clamped again
+	songs := session.Queue.GetSongs()
+	start, end, page, totalPages := paginate(len(songs), page)
+	embed := discord.QueueEmbed(songs[start:end], start, page, totalPages, songs, queueWaits(session, songs))
+
+	var components []discordgo.MessageComponent
+	if len(songs) > 0 {
+		components = discord.QueueControls(page, totalPages)
+	}
+
+	if err := session.InteractionUpdateEmbed(i.Interaction, embed, components); err != nil {
+		log.Printf("Error updating queue embed: %v", err)
+	}
+}
+
+// queueWaits works out how long until each song in the queue plays. Nothing can
+// be worked out while the current song's length is unknown, e.g. a live stream.
+func queueWaits(session *discord.Session, songs []*queue.Track) []time.Duration {
+	var remaining time.Duration
+	if current := session.Player.CurrentSong(); current != nil {
+		if current.Length <= 0 {
+			return nil
+		}
+		remaining = max(current.Length-session.Player.Position(), 0)
+	}
+	return queue.WaitTimes(songs, remaining)
+}
+
+// paginate clamps page to the pages available for total items and returns the
+// bounds of that page along with the page count, which is at least 1.
+func paginate(total int, page int) (start int, end int, clamped int, totalPages int) {
+	totalPages = int(math.Ceil(float64(total) / float64(songsPerPage)))
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	start = (page - 1) * songsPerPage
+	end = start + songsPerPage
+	if end > total {
+		end = total
 	}
+	return start, end, page, totalPages
 }
 
 func init() {
@@ -60,15 +105,8 @@ func init() {
 		Definition: &discordgo.ApplicationCommand{
 			Name:        "showqueue",
 			Description: "Shows the current song queue.",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionInteger,
-					Name:        "page",
-					Description: "The page of the queue to view.",
-					Required:    false,
-				},
-			},
 		},
 		Handler: showqueueHandler,
 	}
+	Components[discord.QueueComponent] = Component{Handler: queuePageHandler}
 }
@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+)
+
+func shuffleHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	if session.Queue.Size() < 2 {
+		session.InteractionRespond(i.Interaction, "There need to be at least two songs in the queue to shuffle.")
+		return
+	}
+
+	moved := session.Queue.Shuffle()
+	session.InteractionRespond(i.Interaction, fmt.Sprintf("🔀 Shuffled the queue, %d of %d songs changed position.", moved, session.Queue.Size()))
+}
+
+func init() {
+	Commands["shuffle"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "shuffle",
+			Description: "Shuffles the songs in the queue.",
+		},
+		Handler:    shuffleHandler,
+		Permission: PermissionDJ,
+	}
+}
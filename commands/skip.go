@@ -1,11 +1,12 @@
 package commands
 
 import (
+	"context"
 	"github.com/bwmarrin/discordgo"
 	"github.com/coreyo-git/beatgopher/discord"
 )
 
-func skipHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func skipHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
 	session := discord.GetOrCreateSession(s, i)
 	if session.Player.Skip() {
 		session.InteractionRespond(i.Interaction, "Skipped the current song.")
@@ -20,6 +21,8 @@ func init() {
 			Name:        "skip",
 			Description: "Skips the current song.",
 		},
-		Handler: skipHandler,
+		Handler:    skipHandler,
+		Permission: PermissionDJ,
+		OwnSong:    requestedCurrentSong,
 	}
 }
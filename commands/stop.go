@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"math/rand"
 	"time"
 
@@ -21,7 +22,7 @@ var notInChannelReplies = []string{
 	"You want me to stop... nothing? That's already stopped! 🛑",
 }
 
-func stopHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func stopHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
 	session := discord.GetOrCreateSession(s, i)
 
 	if !session.IsVoiceConnected() {
@@ -43,6 +44,7 @@ func init() {
 			Name:        "stop",
 			Description: "Stops and disconnects the bot.",
 		},
-		Handler: stopHandler,
+		Handler:    stopHandler,
+		Permission: PermissionDJ,
 	}
 }
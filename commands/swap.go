@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+)
+
+func swapHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	songs := session.Queue.GetSongs()
+	if len(songs) < 2 {
+		session.InteractionRespond(i.Interaction, "There need to be at least two songs in the queue to swap.")
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	optionMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		optionMap[opt.Name] = opt
+	}
+
+	first := int(optionMap["first"].IntValue())
+	second := int(optionMap["second"].IntValue())
+	if first > len(songs) || second > len(songs) {
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("❌ Invalid position. Please specify positions between 1 and %d", len(songs)))
+		return
+	}
+
+	// Positions are 1-indexed for users
+	if !session.Queue.Swap(first-1, second-1) {
+		session.InteractionRespond(i.Interaction, "❌ Could not swap those songs, the queue changed. Check /showqueue and try again.")
+		return
+	}
+
+	session.InteractionRespond(i.Interaction, fmt.Sprintf("✅ Swapped **%s** and **%s**.", songs[first-1].Title, songs[second-1].Title))
+}
+
+func init() {
+	Commands["swap"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "swap",
+			Description: "Swap the positions of two songs in the queue.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "first",
+					Description: "The position of the first song (1-indexed, use /showqueue to see positions).",
+					Required:    true,
+					MinValue:    func() *float64 { v := 1.0; return &v }(),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "second",
+					Description: "The position of the second song.",
+					Required:    true,
+					MinValue:    func() *float64 { v := 1.0; return &v }(),
+				},
+			},
+		},
+		Handler:    swapHandler,
+		Permission: PermissionDJ,
+	}
+}
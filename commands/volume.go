@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/player"
+)
+
+func volumeHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("🔊 Volume is at **%d%%**.", session.Player.GetVolume()))
+		return
+	}
+
+	level := int(options[0].IntValue())
+	if err := session.Player.SetVolume(level); err != nil {
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	session.InteractionRespond(i.Interaction, fmt.Sprintf("%s Volume set to **%d%%**.", volumeEmoji(level), level))
+}
+
+// volumeEmoji picks a speaker emoji to match the volume level
+func volumeEmoji(level int) string {
+	switch {
+	case level == 0:
+		return "🔇"
+	case level < 50:
+		return "🔈"
+	case level <= player.DefaultVolume:
+		return "🔉"
+	default:
+		return "🔊"
+	}
+}
+
+func init() {
+	Commands["volume"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "volume",
+			Description: "Shows or sets the playback volume.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "level",
+					Description: fmt.Sprintf("Volume as a percentage (0-%d, default %d).", player.MaxVolume, player.DefaultVolume),
+					Required:    false,
+					MinValue:    func() *float64 { v := 0.0; return &v }(),
+					MaxValue:    player.MaxVolume,
+				},
+			},
+		},
+		Handler:    volumeHandler,
+		Permission: PermissionDJ,
+	}
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/player"
+)
+
+func voteskipHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	session := discord.GetOrCreateSession(s, i)
+
+	song := session.Player.CurrentSong()
+	if song == nil {
+		session.InteractionRespond(i.Interaction, "Nothing to skip.")
+		return
+	}
+
+	vote, err := castSkipVote(session, i)
+	if err != nil {
+		session.InteractionRespondEphemeral(i.Interaction, voteMessage(err))
+		return
+	}
+
+	switch {
+	case vote.Skipped:
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("⏭️ Vote passed, skipping **%s**.", song.Title))
+	case vote.AlreadyVoted:
+		session.InteractionRespondEphemeral(i.Interaction, fmt.Sprintf("You've already voted to skip **%s** (%d/%d).", song.Title, vote.Votes, vote.Needed))
+	default:
+		session.InteractionRespond(i.Interaction, fmt.Sprintf("🗳️ **%s** voted to skip **%s** (%d/%d).", interactionUserName(i), song.Title, vote.Votes, vote.Needed))
+		session.ShowSkipVotes(vote)
+	}
+}
+
+// castSkipVote records the member's vote to skip the current song, only
+// listeners in the bot's voice channel can vote.
+func castSkipVote(session *discord.Session, i *discordgo.InteractionCreate) (player.SkipVote, error) {
+	listeners := session.VoiceListeners()
+	if !slices.Contains(listeners, interactionUserID(i)) {
+		return player.SkipVote{}, errors.New("only listeners in the voice channel can vote")
+	}
+
+	needed := player.VotesNeeded(len(listeners), discord.GetSettings(i.GuildID).VoteSkipPercent)
+	return session.Player.VoteSkip(interactionUserID(i), needed), nil
+}
+
+// voteMessage turns a vote that wasn't counted into a reply for the user
+func voteMessage(err error) string {
+	return fmt.Sprintf("🚫 Sorry, %s.", err)
+}
+
+// canSkip reports whether the member may skip the current song without a vote
+func canSkip(session *discord.Session, i *discordgo.InteractionCreate) bool {
+	return isDJ(i.Member, discord.GetSettings(i.GuildID).DJRoleID) || requestedCurrentSong(session, i)
+}
+
+func init() {
+	Commands["voteskip"] = Command{
+		Definition: &discordgo.ApplicationCommand{
+			Name:        "voteskip",
+			Description: "Votes to skip the current song.",
+		},
+		Handler: voteskipHandler,
+	}
+}
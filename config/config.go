@@ -1,27 +1,103 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"os"
+	"strconv"
+	"time"
+
+	"github.com/coreyo-git/beatgopher/player"
 )
 
 // Config holds all configuration for the application.
 type Config struct {
-	Token string `json:"token"`
+	Token   string `json:"token"`
+	DataDir string `json:"data_dir"`
+
+	// Defaults for each guild's settings, admins can change them with /settings.
+	DefaultVolume    int           `json:"default_volume"`
+	IdleTimeout      time.Duration `json:"idle_timeout"`
+	MaxTrackLength   time.Duration `json:"max_track_length"`
+	MaxQueueSize     int           `json:"max_queue_size"`
+	MaxTracksPerUser int           `json:"max_tracks_per_user"`
+	VoteSkipPercent  int           `json:"vote_skip_percent"`
 }
 
 // Cfg is a global/package-level variable that holds the loaded configuration.
+// It is nil until Load is called.
 var Cfg *Config
 
-// Init is executed when the package is imported.
-func init() {
+// Load reads the configuration from the environment and stores it in Cfg.
+func Load() (*Config, error) {
 	token := os.Getenv("DISCORD_TOKEN")
 	if token == "" {
-		log.Fatal("DISCORD_TOKEN environment variable is required")
+		return nil, errors.New("DISCORD_TOKEN environment variable is required")
+	}
+
+	// DataDir is where state that should survive restarts is kept, e.g. saved queues.
+	dataDir := os.Getenv("DATA_DIR")
+	if dataDir == "" {
+		dataDir = "data"
+	}
+
+	cfg := &Config{
+		Token:   token,
+		DataDir: dataDir,
+	}
+
+	var err error
+	if cfg.DefaultVolume, err = envInt("DEFAULT_VOLUME", 100); err != nil {
+		return nil, err
+	}
+	if cfg.DefaultVolume > player.MaxVolume {
+		return nil, fmt.Errorf("DEFAULT_VOLUME must be between 0 and %d, got %d", player.MaxVolume, cfg.DefaultVolume)
+	}
+	if cfg.IdleTimeout, err = envDuration("IDLE_TIMEOUT", 5*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.MaxTrackLength, err = envDuration("MAX_TRACK_LENGTH", 2*time.Hour); err != nil {
+		return nil, err
 	}
+	if cfg.MaxQueueSize, err = envInt("MAX_QUEUE_SIZE", 500); err != nil {
+		return nil, err
+	}
+	if cfg.MaxTracksPerUser, err = envInt("MAX_TRACKS_PER_USER", 0); err != nil {
+		return nil, err
+	}
+	if cfg.VoteSkipPercent, err = envInt("VOTE_SKIP_PERCENT", 50); err != nil {
+		return nil, err
+	}
+	if cfg.VoteSkipPercent < 1 || cfg.VoteSkipPercent > 100 {
+		return nil, fmt.Errorf("VOTE_SKIP_PERCENT must be between 1 and 100, got %d", cfg.VoteSkipPercent)
+	}
+
+	Cfg = cfg
+	return cfg, nil
+}
 
-	// Cfg is initialized with the loaded configuration values.
-	Cfg = &Config{
-		Token: token,
+// envDuration reads a duration such as "90m" from the environment, using fallback if it isn't set.
+func envDuration(name string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s must be a duration such as 90m, got %q", name, value)
+	}
+	return d, nil
+}
+
+// envInt reads a whole number from the environment, using fallback if it isn't set.
+func envInt(name string, fallback int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a whole number, got %q", name, value)
 	}
+	return n, nil
 }
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadRequiresToken(t *testing.T) {
+	t.Setenv("DISCORD_TOKEN", "")
+
+	if _, err := Load(); err == nil {
+		t.Error("Expected an error without DISCORD_TOKEN")
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	t.Setenv("DISCORD_TOKEN", "token")
+	for _, name := range []string{"DATA_DIR", "DEFAULT_VOLUME", "IDLE_TIMEOUT", "MAX_TRACK_LENGTH", "MAX_QUEUE_SIZE", "MAX_TRACKS_PER_USER", "VOTE_SKIP_PERCENT"} {
+		t.Setenv(name, "")
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.DataDir != "data" || cfg.DefaultVolume != 100 || cfg.IdleTimeout != 5*time.Minute || cfg.VoteSkipPercent != 50 {
+		t.Errorf("Unexpected defaults: %+v", cfg)
+	}
+	if Cfg != cfg {
+		t.Error("Expected Load to set Cfg")
+	}
+}
+
+func TestLoadReadsEnvironment(t *testing.T) {
+	t.Setenv("DISCORD_TOKEN", "token")
+	t.Setenv("MAX_TRACK_LENGTH", "90m")
+	t.Setenv("MAX_QUEUE_SIZE", "50")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.MaxTrackLength != 90*time.Minute || cfg.MaxQueueSize != 50 {
+		t.Errorf("Expected values from the environment, got %+v", cfg)
+	}
+}
+
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	t.Setenv("DISCORD_TOKEN", "token")
+
+	t.Setenv("MAX_QUEUE_SIZE", "lots")
+	if _, err := Load(); err == nil {
+		t.Error("Expected an error for a queue size that isn't a number")
+	}
+
+	t.Setenv("MAX_QUEUE_SIZE", "")
+	t.Setenv("IDLE_TIMEOUT", "soon")
+	if _, err := Load(); err == nil {
+		t.Error("Expected an error for an idle timeout that isn't a duration")
+	}
+
+	t.Setenv("IDLE_TIMEOUT", "")
+	t.Setenv("VOTE_SKIP_PERCENT", "150")
+	if _, err := Load(); err == nil {
+		t.Error("Expected an error for a vote share over 100%")
+	}
+
+	t.Setenv("VOTE_SKIP_PERCENT", "")
+	t.Setenv("DEFAULT_VOLUME", "250")
+	if _, err := Load(); err == nil {
+		t.Error("Expected an error for a default volume over 200")
+	}
+}
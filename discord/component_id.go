@@ -0,0 +1,27 @@
+package discord
+
+import "strings"
+
+// componentSeparator splits a custom ID into its handler prefix and any state carried with it.
+const componentSeparator = ":"
+
+// SearchComponent is the custom ID prefix of the /search results menu,
+// its ID carries the interaction the results were sent for e.g. "search:1234".
+const SearchComponent = "search"
+
+// ComponentID builds a custom ID that routes back to the handler registered under prefix.
+func ComponentID(prefix string, state ...string) string {
+	return strings.Join(append([]string{prefix}, state...), componentSeparator)
+}
+
+// ComponentPrefix returns the handler prefix of a custom ID.
+func ComponentPrefix(customID string) string {
+	prefix, _, _ := strings.Cut(customID, componentSeparator)
+	return prefix
+}
+
+// ComponentState returns the state stored in a custom ID after its prefix.
+func ComponentState(customID string) []string {
+	parts := strings.Split(customID, componentSeparator)
+	return parts[1:]
+}
@@ -0,0 +1,106 @@
+package discord
+
+import (
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/player"
+)
+
+// ControlsComponent is the custom ID prefix of the now playing buttons,
+// each button's ID is the prefix followed by its action e.g. "controls:skip".
+const ControlsComponent = "controls"
+
+// Actions carried by the now playing buttons.
+const (
+	ControlPause   = "pause"
+	ControlSkip    = "skip"
+	ControlStop    = "stop"
+	ControlLoop    = "loop"
+	ControlShuffle = "shuffle"
+)
+
+// NowPlayingControls builds the row of buttons shown under the "Playing!" embed.
+// The pause button becomes resume while paused and the loop button shows the current mode.
+func NowPlayingControls(paused bool, loop player.LoopMode) []discordgo.MessageComponent {
+	pause := discordgo.Button{
+		Label:    "Pause",
+		Style:    discordgo.SecondaryButton,
+		Emoji:    &discordgo.ComponentEmoji{Name: "⏸️"},
+		CustomID: controlID(ControlPause),
+	}
+	if paused {
+		pause.Label = "Resume"
+		pause.Style = discordgo.SuccessButton
+		pause.Emoji = &discordgo.ComponentEmoji{Name: "▶️"}
+	}
+
+	loopButton := discordgo.Button{
+		Label:    "Loop: " + loop.String(),
+		Style:    discordgo.SecondaryButton,
+		Emoji:    &discordgo.ComponentEmoji{Name: "🔁"},
+		CustomID: controlID(ControlLoop),
+	}
+	if loop != player.LoopOff {
+		loopButton.Style = discordgo.PrimaryButton
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				pause,
+				discordgo.Button{
+					Label:    "Skip",
+					Style:    discordgo.SecondaryButton,
+					Emoji:    &discordgo.ComponentEmoji{Name: "⏭️"},
+					CustomID: controlID(ControlSkip),
+				},
+				discordgo.Button{
+					Label:    "Stop",
+					Style:    discordgo.DangerButton,
+					Emoji:    &discordgo.ComponentEmoji{Name: "⏹️"},
+					CustomID: controlID(ControlStop),
+				},
+				loopButton,
+				discordgo.Button{
+					Label:    "Shuffle",
+					Style:    discordgo.SecondaryButton,
+					Emoji:    &discordgo.ComponentEmoji{Name: "🔀"},
+					CustomID: controlID(ControlShuffle),
+				},
+			},
+		},
+	}
+}
+
+func controlID(action string) string {
+	return ComponentID(ControlsComponent, action)
+}
+
+// QueueComponent is the custom ID prefix of the queue page buttons,
+// each button's ID carries the page it goes to e.g. "queue:3".
+const QueueComponent = "queue"
+
+// QueueControls builds the Previous and Next buttons for a page of the queue.
+func QueueControls(page int, totalPages int) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Previous",
+					Style:    discordgo.SecondaryButton,
+					Emoji:    &discordgo.ComponentEmoji{Name: "◀️"},
+					CustomID: ComponentID(QueueComponent, strconv.Itoa(page-1)),
+					Disabled: page <= 1,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					Emoji:    &discordgo.ComponentEmoji{Name: "▶️"},
+					CustomID: ComponentID(QueueComponent, strconv.Itoa(page+1)),
+					Disabled: page >= totalPages,
+				},
+			},
+		},
+	}
+}
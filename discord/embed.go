@@ -0,0 +1,204 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/player"
+	"github.com/coreyo-git/beatgopher/queue"
+	"github.com/coreyo-git/beatgopher/services"
+	"github.com/coreyo-git/beatgopher/store"
+)
+
+// progressBarWidth is the number of segments in the now playing progress bar.
+const progressBarWidth = 20
+
+// NowPlaying holds everything shown in the now playing embed.
+type NowPlaying struct {
+	Song     *queue.Track
+	Elapsed  time.Duration
+	Paused   bool
+	LoopMode string
+	Volume   int
+	// SkipVote is the progress of a vote to skip the song, shown once someone has voted
+	SkipVote player.SkipVote
+}
+
+// NowPlayingEmbed builds an embed showing the current song and how far into it we are.
+func NowPlayingEmbed(np NowPlaying) *discordgo.MessageEmbed {
+	song := np.Song
+
+	elapsed := services.FormatDuration(np.Elapsed)
+	bar := progressBar(np.Elapsed, 0, progressBarWidth)
+	total := song.Duration
+	if song.Length > 0 {
+		bar = progressBar(np.Elapsed, song.Length, progressBarWidth)
+		total = services.FormatDuration(song.Length)
+	}
+
+	state := "▶️"
+	if np.Paused {
+		state = "⏸️"
+	}
+
+	description := fmt.Sprintf("Channel: **%s**", song.Channel)
+	if song.RequesterName != "" {
+		description += fmt.Sprintf("\nRequested by: **%s**", song.RequesterName)
+	}
+	description += fmt.Sprintf("\n\n%s %s\n`%s / %s`", state, bar, elapsed, total)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       song.Title,
+		URL:         song.URL,
+		Description: description,
+		Color:       0x1DB954, // Spotify green, or choose any hex color
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Loop: %s • Volume: %d%%", np.LoopMode, np.Volume),
+		},
+	}
+	if song.Thumbnail != "NA" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{
+			URL: song.Thumbnail,
+		}
+	}
+	if np.SkipVote.Votes > 0 {
+		SetSkipVotes(embed, np.SkipVote)
+	}
+
+	return embed
+}
+
+// skipVotesField is the name of the embed field showing a vote to skip.
+const skipVotesField = "Votes to skip"
+
+// SetSkipVotes shows the progress of a vote to skip on a song's embed, replacing any earlier count.
+func SetSkipVotes(embed *discordgo.MessageEmbed, vote player.SkipVote) {
+	field := &discordgo.MessageEmbedField{
+		Name:   skipVotesField,
+		Value:  fmt.Sprintf("🗳️ %d/%d", vote.Votes, vote.Needed),
+		Inline: true,
+	}
+	for i, existing := range embed.Fields {
+		if existing.Name == skipVotesField {
+			embed.Fields[i] = field
+			return
+		}
+	}
+	embed.Fields = append(embed.Fields, field)
+}
+
+// progressBar renders a text progress bar such as ▬▬▬▬🔘▬▬▬▬▬.
+// When the total is unknown the marker stays at the start.
+func progressBar(elapsed, total time.Duration, width int) string {
+	position := 0
+	if total > 0 {
+		position = int(float64(elapsed) / float64(total) * float64(width))
+	}
+	if position >= width {
+		position = width - 1
+	}
+	if position < 0 {
+		position = 0
+	}
+
+	return strings.Repeat("▬", position) + "🔘" + strings.Repeat("▬", width-position-1)
+}
+
+// HistoryEmbed builds an embed listing one page of recently played tracks, most recent first.
+// start is the overall index of the first entry so numbering carries across pages.
+func HistoryEmbed(entries []player.HistoryEntry, start int, page int, totalPages int) *discordgo.MessageEmbed {
+	var description strings.Builder
+	for j, entry := range entries {
+		fmt.Fprintf(&description, "%d. [%s](%s) <t:%d:R>%s\n", start+j+1, entry.Track.Title, entry.Track.URL, entry.PlayedAt.Unix(), requestedBy(entry.Track))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "Recently Played",
+		Description: description.String(),
+		Color:       0x1DB954,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Page %d of %d", page, totalPages),
+		},
+	}
+}
+
+// QueueEmbed builds an embed listing one page of the queue. start is the overall
+// index of the first track and all is the whole queue, used for the totals in the footer.
+// waits holds how long until each track in all plays, as far as it is known.
+func QueueEmbed(tracks []*queue.Track, start int, page int, totalPages int, all []*queue.Track, waits []time.Duration) *discordgo.MessageEmbed {
+	var description strings.Builder
+	if len(tracks) == 0 {
+		description.WriteString("The queue is empty.")
+	}
+	for j, track := range tracks {
+		eta := ""
+		if start+j < len(waits) {
+			eta = " • in " + services.FormatDuration(waits[start+j])
+		}
+		fmt.Fprintf(&description, "%d. [%s](%s) `[%s]`%s%s\n", start+j+1, track.Title, track.URL, track.Duration, eta, requestedBy(track))
+	}
+
+	total, unknown := queue.TotalDuration(all)
+	length := services.FormatDuration(total)
+	if unknown > 0 {
+		length += "+"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "Queue",
+		Description: description.String(),
+		Color:       0x1DB954,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Page %d of %d • %d tracks • %s total", page, totalPages, len(all), length),
+		},
+	}
+}
+
+// SettingsEmbed builds an embed listing a guild's settings.
+func SettingsEmbed(settings store.GuildSettings) *discordgo.MessageEmbed {
+	djRole := "Everyone"
+	if settings.DJRoleID != "" {
+		djRole = fmt.Sprintf("<@&%s>", settings.DJRoleID)
+	}
+	announce := "Where the bot was first used"
+	if settings.AnnounceChannelID != "" {
+		announce = fmt.Sprintf("<#%s>", settings.AnnounceChannelID)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: "Settings",
+		Color: 0x1DB954,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Default volume", Value: fmt.Sprintf("%d%%", settings.DefaultVolume), Inline: true},
+			{Name: "Loop default", Value: settings.LoopDefault, Inline: true},
+			{Name: "Idle timeout", Value: durationSetting(settings.IdleTimeout, "Never leave"), Inline: true},
+			{Name: "DJ role", Value: djRole, Inline: true},
+			{Name: "Announce channel", Value: announce, Inline: true},
+			{Name: "Longest song", Value: durationSetting(settings.MaxTrackLength, "No limit"), Inline: true},
+			{Name: "Queue size", Value: countSetting(settings.MaxQueueSize), Inline: true},
+			{Name: "Songs per person", Value: countSetting(settings.MaxTracksPerUser), Inline: true},
+			{Name: "Votes to skip", Value: fmt.Sprintf("%d%% of listeners", settings.VoteSkipPercent), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Change with /settings set, undo with /settings reset",
+		},
+	}
+}
+
+// durationSetting shows a length of time setting, where zero means off
+func durationSetting(d time.Duration, off string) string {
+	if d <= 0 {
+		return off
+	}
+	return services.FormatDuration(d)
+}
+
+// countSetting shows a limit setting, where zero means no limit
+func countSetting(n int) string {
+	if n <= 0 {
+		return "No limit"
+	}
+	return fmt.Sprint(n)
+}
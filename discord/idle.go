@@ -0,0 +1,84 @@
+package discord
+
+import (
+	"log"
+	"time"
+)
+
+// HandleListenerChange runs when someone joins or leaves a voice channel. The bot
+// leaves once it has been on its own in its channel for the guild's idle timeout.
+func HandleListenerChange(guildID string) {
+	sessionsMutex.Lock()
+	session, exists := sessions[guildID]
+	sessionsMutex.Unlock()
+	if !exists || !session.IsVoiceConnected() {
+		return
+	}
+
+	if len(session.VoiceListeners()) == 0 {
+		session.startEmptyTimer()
+	} else {
+		session.stopTimer(&session.emptyTimer)
+	}
+}
+
+// startIdleTimer leaves the voice channel if nothing is queued before the idle timeout.
+func (s *Session) startIdleTimer() {
+	s.startLeaveTimer(&s.idleTimer, func() bool {
+		return !s.Player.IsPlayerPlaying()
+	}, "👋 Nothing left to play, so I've left the voice channel.")
+}
+
+// startEmptyTimer leaves the voice channel if no one comes back before the idle timeout.
+func (s *Session) startEmptyTimer() {
+	s.startLeaveTimer(&s.emptyTimer, func() bool {
+		return len(s.VoiceListeners()) == 0
+	}, "👋 Everyone left, so I've stopped playing and left the voice channel.")
+}
+
+// startLeaveTimer stops the player and says goodbye once the guild's idle timeout
+// passes, as long as stillIdle agrees. A timer that is already running is left alone.
+func (s *Session) startLeaveTimer(timer **time.Timer, stillIdle func() bool, goodbye string) {
+	timeout := GetSettings(s.GuildID).IdleTimeout
+	if timeout <= 0 {
+		return
+	}
+
+	s.timersMu.Lock()
+	defer s.timersMu.Unlock()
+	if *timer != nil {
+		return
+	}
+
+	var t *time.Timer
+	t = time.AfterFunc(timeout, func() {
+		s.timersMu.Lock()
+		current := *timer == t
+		if current {
+			*timer = nil
+		}
+		s.timersMu.Unlock()
+
+		// The timer was stopped, or things picked up again, while it was firing
+		if !current || !stillIdle() || !s.IsVoiceConnected() {
+			return
+		}
+
+		log.Printf("Leaving voice in guild %s after %v idle", s.GuildID, timeout)
+		if err := s.SendChannelMessage(goodbye); err != nil {
+			log.Printf("Error sending goodbye message: %v", err)
+		}
+		s.Player.Stop()
+	})
+	*timer = t
+}
+
+// stopTimer cancels a leave timer if it's running.
+func (s *Session) stopTimer(timer **time.Timer) {
+	s.timersMu.Lock()
+	defer s.timersMu.Unlock()
+	if *timer != nil {
+		(*timer).Stop()
+		*timer = nil
+	}
+}
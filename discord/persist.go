@@ -0,0 +1,293 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/queue"
+	"github.com/coreyo-git/beatgopher/store"
+)
+
+const (
+	// saveDelay batches bursts of queue changes, e.g. a playlist being added, into a single save
+	saveDelay = 2 * time.Second
+	// resumeWindow is how long after a restart a saved queue is still offered
+	resumeWindow = 24 * time.Hour
+)
+
+var (
+	// queueStore persists guild queues between restarts, nil disables persistence
+	queueStore store.QueueStoreInterface
+
+	// shuttingDown stops snapshots being deleted as the bot leaves voice channels on exit
+	shuttingDown atomic.Bool
+
+	offerResumeOnce sync.Once
+	pendingMutex    sync.Mutex
+	// Map of guild IDs to snapshots that were saved before the last restart
+	pendingResumes = make(map[string]*store.GuildSnapshot)
+)
+
+// SetQueueStore sets where guild queues are saved so they survive restarts.
+func SetQueueStore(qs store.QueueStoreInterface) {
+	queueStore = qs
+}
+
+// persistentQueue wraps a queue and schedules a snapshot whenever it changes.
+type persistentQueue struct {
+	queue.QueueInterface
+	onChange func()
+}
+
+func (pq *persistentQueue) Enqueue(track *queue.Track) {
+	pq.QueueInterface.Enqueue(track)
+	pq.onChange()
+}
+
+func (pq *persistentQueue) Dequeue() *queue.Track {
+	track := pq.QueueInterface.Dequeue()
+	pq.onChange()
+	return track
+}
+
+func (pq *persistentQueue) RemoveFromQueue(track *queue.Track) bool {
+	removed := pq.QueueInterface.RemoveFromQueue(track)
+	pq.onChange()
+	return removed
+}
+
+func (pq *persistentQueue) Move(from int, to int) bool {
+	moved := pq.QueueInterface.Move(from, to)
+	pq.onChange()
+	return moved
+}
+
+func (pq *persistentQueue) Swap(a int, b int) bool {
+	swapped := pq.QueueInterface.Swap(a, b)
+	pq.onChange()
+	return swapped
+}
+
+func (pq *persistentQueue) InsertAt(index int, track *queue.Track) {
+	pq.QueueInterface.InsertAt(index, track)
+	pq.onChange()
+}
+
+func (pq *persistentQueue) Shuffle() int {
+	moved := pq.QueueInterface.Shuffle()
+	pq.onChange()
+	return moved
+}
+
+func (pq *persistentQueue) RemoveDuplicates() int {
+	removed := pq.QueueInterface.RemoveDuplicates()
+	pq.onChange()
+	return removed
+}
+
+func (pq *persistentQueue) Clear() {
+	pq.QueueInterface.Clear()
+	pq.onChange()
+}
+
+// snapshot captures the session's current playback state
+func (s *Session) snapshot() *store.GuildSnapshot {
+	return &store.GuildSnapshot{
+		GuildID:        s.GetGuildID(),
+		TextChannelID:  s.GetTextChannelID(),
+		VoiceChannelID: s.GetVoiceChannelID(),
+		Current:        s.Player.CurrentSong(),
+		Position:       s.Player.Position(),
+		Queue:          s.Queue.GetSongs(),
+		SavedAt:        time.Now(),
+	}
+}
+
+// keepForResume holds on to the session's queue so /resume can pick it up again,
+// for when playback ended by accident rather than on purpose
+func (s *Session) keepForResume() {
+	snapshot := s.snapshot()
+	if snapshot.IsEmpty() {
+		return
+	}
+
+	pendingMutex.Lock()
+	pendingResumes[snapshot.GuildID] = snapshot
+	pendingMutex.Unlock()
+}
+
+// scheduleSave saves a snapshot shortly, batching up any other changes made in the meantime
+func (s *Session) scheduleSave() {
+	if queueStore == nil {
+		return
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	if s.closed {
+		return
+	}
+
+	if s.saveTimer == nil {
+		s.saveTimer = time.AfterFunc(saveDelay, s.saveSnapshot)
+		return
+	}
+	s.saveTimer.Reset(saveDelay)
+}
+
+// saveSnapshot writes the session's state to the queue store, removing it when there's nothing to resume
+func (s *Session) saveSnapshot() {
+	if queueStore == nil {
+		return
+	}
+
+	s.saveMu.Lock()
+	closed := s.closed
+	s.saveMu.Unlock()
+	if closed {
+		return
+	}
+
+	snapshot := s.snapshot()
+	var err error
+	if snapshot.IsEmpty() {
+		err = queueStore.Delete(snapshot.GuildID)
+	} else {
+		err = queueStore.Save(snapshot)
+	}
+	if err != nil {
+		log.Printf("Error saving queue for guild %s: %v", snapshot.GuildID, err)
+	}
+}
+
+// closeSnapshots stops any pending save and, unless the bot is shutting down,
+// removes the guild's snapshot since playback ended on purpose
+func (s *Session) closeSnapshots() {
+	s.saveMu.Lock()
+	s.closed = true
+	if s.saveTimer != nil {
+		s.saveTimer.Stop()
+	}
+	s.saveMu.Unlock()
+
+	if queueStore == nil || shuttingDown.Load() {
+		return
+	}
+	if err := queueStore.Delete(s.GetGuildID()); err != nil {
+		log.Printf("Error deleting saved queue for guild %s: %v", s.GetGuildID(), err)
+	}
+}
+
+// Shutdown saves a snapshot of every active session so playback can be resumed after a restart.
+func Shutdown() {
+	shuttingDown.Store(true)
+
+	sessionsMutex.Lock()
+	active := make([]*Session, 0, len(sessions))
+	for _, session := range sessions {
+		active = append(active, session)
+	}
+	sessionsMutex.Unlock()
+
+	for _, session := range active {
+		session.saveMu.Lock()
+		if session.saveTimer != nil {
+			session.saveTimer.Stop()
+		}
+		session.saveMu.Unlock()
+
+		session.saveSnapshot()
+	}
+	log.Printf("Saved %d guild queue(s) for shutdown", len(active))
+}
+
+// OfferResume loads the snapshots saved before the last restart and lets each
+// guild know they can pick up where they left off with /resume.
+// Only the first call does anything, so it's safe to call on every Ready event.
+func OfferResume(s *discordgo.Session) {
+	offerResumeOnce.Do(func() {
+		if queueStore == nil {
+			return
+		}
+
+		snapshots, err := queueStore.LoadAll()
+		if err != nil {
+			log.Printf("Error loading saved queues: %v", err)
+			return
+		}
+
+		for _, snapshot := range snapshots {
+			if snapshot.IsEmpty() || time.Since(snapshot.SavedAt) > resumeWindow {
+				queueStore.Delete(snapshot.GuildID)
+				continue
+			}
+
+			pendingMutex.Lock()
+			pendingResumes[snapshot.GuildID] = snapshot
+			pendingMutex.Unlock()
+
+			total := len(snapshot.Queue)
+			if snapshot.Current != nil {
+				total++
+			}
+			message := fmt.Sprintf("👋 I was restarted while playing here. I saved **%d** song(s), use /resume to pick up where we left off.", total)
+			if _, err := s.ChannelMessageSend(snapshot.TextChannelID, message); err != nil {
+				log.Printf("Error offering resume in guild %s: %v", snapshot.GuildID, err)
+			}
+		}
+	})
+}
+
+// HasSavedSession returns true if the guild has a queue saved from before the last restart.
+func HasSavedSession(guildID string) bool {
+	pendingMutex.Lock()
+	defer pendingMutex.Unlock()
+	_, exists := pendingResumes[guildID]
+	return exists
+}
+
+// ResumeSavedSession restores the queue saved before the last restart, rejoining the
+// voice channel it was playing in. It returns the number of tracks restored.
+func ResumeSavedSession(s *discordgo.Session, i *discordgo.InteractionCreate) (int, error) {
+	pendingMutex.Lock()
+	snapshot, exists := pendingResumes[i.GuildID]
+	delete(pendingResumes, i.GuildID)
+	pendingMutex.Unlock()
+	if !exists {
+		return 0, fmt.Errorf("there is no saved queue for this server")
+	}
+
+	session := GetOrCreateSession(s, i)
+
+	// Prefer the channel we were playing in, falling back to the user's channel
+	err := session.joinChannel(snapshot.VoiceChannelID)
+	if err != nil {
+		log.Printf("Could not rejoin saved voice channel in guild %s: %v", i.GuildID, err)
+		if err := session.JoinVoiceChannel(i); err != nil {
+			return 0, err
+		}
+	}
+
+	tracks := []*queue.Track{}
+	var position time.Duration
+	if snapshot.Current != nil {
+		tracks = append(tracks, snapshot.Current)
+		position = snapshot.Position
+	}
+	tracks = append(tracks, snapshot.Queue...)
+
+	restored, err := session.Player.Restore(tracks, position)
+	if restored == 0 {
+		// Nothing is left to play once the limits are applied
+		session.LeaveVoiceChannel()
+		return 0, err
+	}
+	if err != nil {
+		log.Printf("Restored %d of %d songs in guild %s: %v", restored, len(tracks), i.GuildID, err)
+	}
+	return restored, nil
+}
@@ -0,0 +1,118 @@
+package discord
+
+import (
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// reconnectAttempts is how many times the bot tries to rejoin its channel after the voice connection fails
+	reconnectAttempts = 5
+	// reconnectBackoff is the wait before the first attempt, doubling after each failed one
+	reconnectBackoff = time.Second
+)
+
+// superviseVoice waits for a voice connection to end. If it failed, rather than
+// the bot leaving or being disconnected on purpose, it rejoins the same channel
+// so the player can carry on from where it was.
+func (s *Session) superviseVoice(vc *discordgo.VoiceConnection) {
+	<-vc.Dead
+	err := voiceError(vc)
+	if err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.VoiceConnection != vc {
+		// Already replaced, e.g. by /resume joining again
+		s.mu.Unlock()
+		return
+	}
+	channelID := s.VoiceChannelID
+	s.reconnecting = true
+	s.mu.Unlock()
+
+	log.Printf("Voice connection failed in guild %s, reconnecting: %v", s.GuildID, err)
+	ok := s.reconnect(channelID)
+
+	s.mu.Lock()
+	// LeaveVoiceChannel clears reconnecting when the bot is told to leave in the meantime
+	cancelled := !s.reconnecting
+	s.reconnecting = false
+	if !ok {
+		s.VoiceConnection = nil
+		s.VoiceChannelID = ""
+	}
+	s.mu.Unlock()
+
+	if cancelled {
+		if ok {
+			s.LeaveVoiceChannel()
+		}
+		HandleBotDisconnection(s.GuildID)
+		return
+	}
+	if ok {
+		log.Printf("Voice connection recovered in guild %s", s.GuildID)
+		return
+	}
+
+	// Hold on to the queue so /resume can pick up where we stopped
+	log.Printf("Giving up reconnecting to voice in guild %s", s.GuildID)
+	s.keepForResume()
+	s.SendChannelMessage("⚠️ I lost the voice connection and couldn't get it back. Use /resume to pick up where we left off.")
+	HandleBotDisconnection(s.GuildID)
+}
+
+// reconnect tries to join channelID again, backing off between attempts.
+// It stops early if the bot was told to leave or the session was cleaned up in the meantime.
+func (s *Session) reconnect(channelID string) bool {
+	backoff := reconnectBackoff
+	for attempt := 1; attempt <= reconnectAttempts; attempt++ {
+		time.Sleep(backoff)
+		if !s.stillReconnecting() {
+			return false
+		}
+
+		err := s.joinChannel(channelID)
+		if err == nil {
+			return true
+		}
+		log.Printf("Reconnect attempt %d of %d failed in guild %s: %v", attempt, reconnectAttempts, s.GuildID, err)
+		backoff *= 2
+	}
+	return false
+}
+
+// isReconnecting reports whether the voice connection failed and is being
+// brought back, when the player and queue should be left alone.
+func (s *Session) isReconnecting() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.reconnecting {
+		return true
+	}
+	return s.VoiceConnection != nil && voiceError(s.VoiceConnection) != nil
+}
+
+// stillReconnecting reports whether a reconnect should carry on, it stops once the
+// bot is told to leave or the session is no longer the one in use for its guild.
+func (s *Session) stillReconnecting() bool {
+	sessionsMutex.Lock()
+	active := sessions[s.GuildID] == s
+	sessionsMutex.Unlock()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return active && s.reconnecting
+}
+
+// voiceError returns the error a voice connection failed with, nil if it's
+// still up or ended because the bot left or was disconnected.
+func voiceError(vc *discordgo.VoiceConnection) error {
+	vc.Cond.L.Lock()
+	defer vc.Cond.L.Unlock()
+	return vc.Err
+}
@@ -1,14 +1,15 @@
 package discord
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/coreyo-git/beatgopher/player"
 	"github.com/coreyo-git/beatgopher/queue"
-	"github.com/coreyo-git/beatgopher/services"
 )
 
 // DiscordSessionInterface defines the contract for Discord session operations
@@ -16,17 +17,38 @@ type DiscordSessionInterface interface {
 	// InteractionRespond sends a response to an interaction
 	InteractionRespond(i *discordgo.Interaction, content string) error
 
+	// InteractionRespondEphemeral responds to an interaction with a message only the user can see
+	InteractionRespondEphemeral(i *discordgo.Interaction, content string) error
+
+	// InteractionRespondEmbed responds to an interaction with an embed
+	InteractionRespondEmbed(i *discordgo.Interaction, embed *discordgo.MessageEmbed) error
+
+	// InteractionRespondEmbedComponents responds to an interaction with an embed and components such as buttons
+	InteractionRespondEmbedComponents(i *discordgo.Interaction, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) error
+
+	// AutocompleteRespond sends suggestions for the option the user is typing in
+	AutocompleteRespond(i *discordgo.Interaction, choices []*discordgo.ApplicationCommandOptionChoice) error
+
 	// FollowupMessage sends a followup message to an interaction
 	FollowupMessage(i *discordgo.Interaction, content string) error
 
+	// FollowupComponents sends a followup message with message components such as a select menu
+	FollowupComponents(i *discordgo.Interaction, content string, components []discordgo.MessageComponent) error
+
+	// InteractionUpdate responds to a component interaction by replacing the message it is on
+	InteractionUpdate(i *discordgo.Interaction, content string) error
+
+	// InteractionUpdateEmbed responds to a component interaction by replacing the embed and components of its message
+	InteractionUpdateEmbed(i *discordgo.Interaction, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) error
+
 	// SendChannelMessage sends a message to the text channel
 	SendChannelMessage(message string) error
 
 	// SendSongEmbed sends an embed message for a song
-	SendSongEmbed(song *services.YoutubeResult, footer string) error
+	SendSongEmbed(track *queue.Track, footer string) error
 
-	// SendQueueEmbed sends an embed message for the queue
-	SendQueueEmbed(songs []*services.YoutubeResult, currentPage int, totalPages int) error
+	// SendNowPlayingEmbed sends the embed for the song that started playing with player controls
+	SendNowPlayingEmbed(track *queue.Track, footer string) error
 
 	// JoinVoiceChannel joins the voice channel of the user who triggered the interaction
 	JoinVoiceChannel(i *discordgo.InteractionCreate) error
@@ -49,7 +71,7 @@ type DiscordSessionInterface interface {
 	IsVoiceConnected() bool
 
 	// function to remove from the queue 
-	RemoveFromQueue(song *services.YoutubeResult) bool
+	RemoveFromQueue(track *queue.Track) bool
 }
 
 // Session provides helper methods for interacting with the Discord API.
@@ -57,44 +79,112 @@ type Session struct {
 	Session         *discordgo.Session
 	GuildID         string
 	TextChannelID   string
+	VoiceChannelID  string
 	VoiceConnection *discordgo.VoiceConnection
 	Player          player.PlayerInterface
 	Queue           queue.QueueInterface
 	mu              sync.RWMutex
+
+	// commandChannelID is the channel the session was started from, songs are
+	// announced there when the guild has no announce channel. Guarded by mu
+	commandChannelID string
+
+	// nowPlaying is the last "Playing!" message sent, guarded by mu
+	nowPlaying *discordgo.Message
+
+	// reconnecting is set while a failed voice connection is being brought back, guarded by mu
+	reconnecting bool
+
+	// movingTo is the channel MoveToChannel is waiting for Discord to confirm, moved
+	// is closed once it has. Both guarded by mu
+	movingTo string
+	moved    chan struct{}
+
+	// idleTimer and emptyTimer leave the voice channel when there's nothing to do, guarded by timersMu
+	idleTimer  *time.Timer
+	emptyTimer *time.Timer
+	timersMu   sync.Mutex
+
+	// saveTimer batches queue changes into a single snapshot, guarded by saveMu
+	saveTimer *time.Timer
+	closed    bool
+	saveMu    sync.Mutex
 }
 
 var (
 	sessionsMutex sync.Mutex
 	// Map of guild IDs to players.
 	sessions = make(map[string]*Session)
+
+	// appCtx is cancelled when the bot shuts down, killing anything the players are still running
+	appCtx = context.Background()
 )
 
+// SetContext sets the context players run under, cancel it on shutdown.
+func SetContext(ctx context.Context) {
+	appCtx = ctx
+}
+
 // NewSession creates a new Session wrapper.
 func newSession(s *discordgo.Session, i *discordgo.InteractionCreate) *Session {
-	queue := queue.NewQueue()
 	session := &Session{
 		Session:         s,
 		GuildID:         i.GuildID,
 		TextChannelID:   i.ChannelID,
 		VoiceConnection: nil,
-		Queue:           queue,
+		Queue:           nil,
 		Player:			 nil,
 		mu:              sync.RWMutex{},
 	}
 	session.mu.Lock()
 	defer session.mu.Unlock()
 
-	session.Player = player.NewPlayer(
-		queue,
+	// Save a snapshot whenever the queue changes so it survives restarts
+	session.Queue = &persistentQueue{
+		QueueInterface: queue.NewQueue(),
+		onChange:       session.scheduleSave,
+	}
+
+	guildPlayer := player.NewPlayer(
+		appCtx,
+		session.Queue,
 		session.SendSongEmbed,
 		session.IsVoiceConnected,
 		session.GetVoiceConnection,
 		session.LeaveVoiceChannel,
 	)
+	guildPlayer.OnTrackStart = func(track *queue.Track) {
+		session.stopTimer(&session.idleTimer)
+		session.scheduleSave()
+	}
+	guildPlayer.OnIdle = session.startIdleTimer
+	guildPlayer.OnSendNowPlaying = session.SendNowPlayingEmbed
+
+	// Start from the guild's settings
+	settings := GetSettings(i.GuildID)
+	session.commandChannelID = i.ChannelID
+	session.TextChannelID = session.announceChannel(settings)
+	guildPlayer.SetLimits(limitsFrom(settings))
+	if err := guildPlayer.SetVolume(settings.DefaultVolume); err != nil {
+		log.Printf("Ignoring default volume for guild %s: %v", i.GuildID, err)
+	}
+	if mode, err := player.ParseLoopMode(settings.LoopDefault); err == nil {
+		guildPlayer.SetLoopMode(mode)
+	}
+	session.Player = guildPlayer
 
 	return session
 }
 
+// GetSession returns the guild's session if it has one, without creating it.
+func GetSession(guildID string) (*Session, bool) {
+	sessionsMutex.Lock()
+	defer sessionsMutex.Unlock()
+
+	session, exists := sessions[guildID]
+	return session, exists
+}
+
 func GetOrCreateSession(s *discordgo.Session, i *discordgo.InteractionCreate) *Session {
 	sessionsMutex.Lock()
 	defer sessionsMutex.Unlock()
@@ -117,12 +207,23 @@ func HandleBotDisconnection(guildID string) {
 		sessionsMutex.Unlock()
 		return
 	}
+	// The voice connection dropped rather than the bot leaving, superviseVoice is bringing it back
+	if session.isReconnecting() {
+		sessionsMutex.Unlock()
+		log.Printf("Keeping player state while reconnecting to voice in guild: %s", guildID)
+		return
+	}
 	// Remove from map
 	delete(sessions, guildID)
 	sessionsMutex.Unlock()
 
 	log.Printf("Cleaning up player state for disconnected guild: %s", guildID)
 
+	// Playback ended so there's nothing to resume after a restart
+	session.closeSnapshots()
+	session.stopTimer(&session.idleTimer)
+	session.stopTimer(&session.emptyTimer)
+
 	// Clear queue under session lock
 	session.mu.Lock()
 	session.Queue.Clear()
@@ -133,8 +234,8 @@ func HandleBotDisconnection(guildID string) {
 	log.Printf("Session cleanup completed for guild: %s", guildID)
 }
 
-func (s *Session) RemoveFromQueue(song *services.YoutubeResult) bool {
-	return s.Queue.RemoveFromQueue(song)
+func (s *Session) RemoveFromQueue(track *queue.Track) bool {
+	return s.Queue.RemoveFromQueue(track)
 }
 
 // GetGuildID returns the guild ID
@@ -145,6 +246,14 @@ func (s *Session) GetGuildID() string {
 	return s.GuildID
 }
 
+// GetVoiceChannelID returns the ID of the voice channel the bot is in
+func (s *Session) GetVoiceChannelID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.VoiceChannelID
+}
+
 // GetTextChannelID returns the text channel ID
 func (s *Session) GetTextChannelID() string {
 	s.mu.RLock()
@@ -163,6 +272,54 @@ func (s *Session) InteractionRespond(i *discordgo.Interaction, content string) e
 	})
 }
 
+// InteractionRespondEphemeral responds to an interaction with a message only the user who used it can see.
+func (s *Session) InteractionRespondEphemeral(i *discordgo.Interaction, content string) error {
+	return s.Session.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// InteractionRespondEmbed responds to an interaction with an embed.
+func (s *Session) InteractionRespondEmbed(i *discordgo.Interaction, embed *discordgo.MessageEmbed) error {
+	return s.Session.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+}
+
+// AutocompleteRespond sends suggestions for the option the user is typing in.
+func (s *Session) AutocompleteRespond(i *discordgo.Interaction, choices []*discordgo.ApplicationCommandOptionChoice) error {
+	return AutocompleteRespond(s.Session, i, choices)
+}
+
+// AutocompleteRespond sends suggestions without needing a guild session, so
+// typing in a guild that isn't playing anything doesn't start a player.
+func AutocompleteRespond(s *discordgo.Session, i *discordgo.Interaction, choices []*discordgo.ApplicationCommandOptionChoice) error {
+	return s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+}
+
+// InteractionRespondEmbedComponents responds to an interaction with an embed and components such as buttons.
+func (s *Session) InteractionRespondEmbedComponents(i *discordgo.Interaction, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) error {
+	return s.Session.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+}
+
 // FollowupMessage is a wrapper for s.FollowupMessageCreate that simplifies sending a followup message.
 func (s *Session) FollowupMessage(i *discordgo.Interaction, content string) error {
 	_, err := s.Session.FollowupMessageCreate(i, true, &discordgo.WebhookParams{
@@ -171,19 +328,113 @@ func (s *Session) FollowupMessage(i *discordgo.Interaction, content string) erro
 	return err
 }
 
+// FollowupComponents sends a followup message with message components such as a select menu.
+func (s *Session) FollowupComponents(i *discordgo.Interaction, content string, components []discordgo.MessageComponent) error {
+	_, err := s.Session.FollowupMessageCreate(i, true, &discordgo.WebhookParams{
+		Content:    content,
+		Components: components,
+	})
+	return err
+}
+
+// InteractionUpdate responds to a component interaction by replacing the content of
+// the message it is on and removing its components.
+func (s *Session) InteractionUpdate(i *discordgo.Interaction, content string) error {
+	return s.Session.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+// InteractionUpdateEmbed responds to a component interaction by replacing the embed
+// and components of the message it is on.
+func (s *Session) InteractionUpdateEmbed(i *discordgo.Interaction, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) error {
+	if components == nil {
+		components = []discordgo.MessageComponent{}
+	}
+	return s.Session.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+}
+
 func (s *Session) SendChannelMessage(message string) error {
-	_, err := s.Session.ChannelMessageSend(s.TextChannelID, message)
+	_, err := s.Session.ChannelMessageSend(s.GetTextChannelID(), message)
 	if err != nil {
 		return fmt.Errorf("error sending channel message: %v", err)
 	}
 	return nil
 }
 
-func (s *Session) SendSongEmbed(song *services.YoutubeResult, footer string) error {
+func (s *Session) SendSongEmbed(song *queue.Track, footer string) error {
+	_, err := s.Session.ChannelMessageSendEmbed(s.GetTextChannelID(), songEmbed(song, footer))
+	if err != nil {
+		return fmt.Errorf("error sending song embed: %v", err)
+	}
+	return nil
+}
+
+// SendNowPlayingEmbed sends the song embed with a row of buttons to control the player.
+func (s *Session) SendNowPlayingEmbed(song *queue.Track, footer string) error {
+	message, err := s.Session.ChannelMessageSendComplex(s.GetTextChannelID(), &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{songEmbed(song, footer)},
+		Components: NowPlayingControls(false, s.Player.GetLoopMode()),
+	})
+	if err != nil {
+		return fmt.Errorf("error sending now playing embed: %v", err)
+	}
+
+	s.mu.Lock()
+	s.nowPlaying = message
+	s.mu.Unlock()
+	return nil
+}
+
+// ShowSkipVotes updates the "Playing!" embed for the current song with the progress of a vote to skip it.
+func (s *Session) ShowSkipVotes(vote player.SkipVote) {
+	s.mu.RLock()
+	sent := s.nowPlaying
+	s.mu.RUnlock()
+
+	current := s.Player.CurrentSong()
+	if sent == nil || current == nil {
+		return
+	}
+
+	// Fetch the message again so changes made by the player buttons are kept
+	message, err := s.Session.ChannelMessage(sent.ChannelID, sent.ID)
+	if err != nil {
+		log.Printf("Error fetching now playing message: %v", err)
+		return
+	}
+	if len(message.Embeds) == 0 || message.Embeds[0].URL != current.URL {
+		return
+	}
+
+	embed := message.Embeds[0]
+	SetSkipVotes(embed, vote)
+	if _, err := s.Session.ChannelMessageEditEmbed(sent.ChannelID, sent.ID, embed); err != nil {
+		log.Printf("Error updating skip votes: %v", err)
+	}
+}
+
+// songEmbed builds the embed announcing a song
+func songEmbed(song *queue.Track, footer string) *discordgo.MessageEmbed {
+	description := fmt.Sprintf("Channel: **%s**\nDuration: `%s`", song.Channel, song.Duration)
+	if song.RequesterName != "" {
+		description += fmt.Sprintf("\nRequested by: **%s**", song.RequesterName)
+	}
+
 	embed := &discordgo.MessageEmbed{
 		Title:       song.Title,
 		URL:         song.URL,
-		Description: fmt.Sprintf("Channel: **%s**\nDuration: `%s`", song.Channel, song.Duration),
+		Description: description,
 		Color:       0x1DB954, // Spotify green, or choose any hex color
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: footer,
@@ -195,38 +446,13 @@ func (s *Session) SendSongEmbed(song *services.YoutubeResult, footer string) err
 		}
 	}
 
-	_, err := s.Session.ChannelMessageSendEmbed(s.TextChannelID, embed)
-	if err != nil {
-		return fmt.Errorf("error sending song embed: %v", err)
-	}
-	return nil
+	return embed
 }
 
-func (s *Session) SendQueueEmbed(songs []*services.YoutubeResult, currentPage int, totalPages int) error {
-	embed := &discordgo.MessageEmbed{
-		Title: "Queue",
-		Color: 0x1DB954, // Spotify green, or choose any hex color
-	}
-
-	var description string
-	if len(songs) == 0 {
-		description = "The queue is empty."
-	} else {
-		for i, song := range songs {
-			description += fmt.Sprintf("%d. [%s](%s) `[%s]`\n", i+1, song.Title, song.URL, song.Duration)
-		}
-	}
-
-	embed.Description = description
-	if totalPages > 1 {
-		embed.Footer = &discordgo.MessageEmbedFooter{
-			Text: fmt.Sprintf("Page %d of %d", currentPage, totalPages),
-		}
-	}
-
-	_, err := s.Session.ChannelMessageSendEmbed(s.TextChannelID, embed)
-	if err != nil {
-		return fmt.Errorf("error sending song embed: %v", err)
+// requestedBy formats who queued a track for the end of a queue line
+func requestedBy(track *queue.Track) string {
+	if track.RequesterName == "" {
+		return ""
 	}
-	return nil
+	return fmt.Sprintf(" • %s", track.RequesterName)
 }
@@ -0,0 +1,144 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/coreyo-git/beatgopher/player"
+	"github.com/coreyo-git/beatgopher/store"
+)
+
+var (
+	// settingsStore persists guild settings, nil keeps them in memory only
+	settingsStore store.SettingsStoreInterface
+
+	settingsMutex sync.Mutex
+	// defaultSettings are used by guilds that haven't changed anything
+	defaultSettings = store.GuildSettings{
+		DefaultVolume:   player.DefaultVolume,
+		LoopDefault:     player.LoopOff.String(),
+		VoteSkipPercent: player.DefaultVoteSkipPercent,
+	}
+	// Map of guild IDs to their settings, loaded on first use
+	guildSettings = make(map[string]store.GuildSettings)
+)
+
+// SetSettingsStore sets where guild settings are saved.
+func SetSettingsStore(ss store.SettingsStoreInterface) {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+	settingsStore = ss
+}
+
+// SetDefaultSettings sets the settings used by guilds that haven't changed them.
+func SetDefaultSettings(settings store.GuildSettings) {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+	defaultSettings = settings
+}
+
+// DefaultSettings returns the settings used by guilds that haven't changed them.
+func DefaultSettings() store.GuildSettings {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+	return defaultSettings
+}
+
+// GetSettings returns a guild's settings, or the defaults if it hasn't changed them.
+func GetSettings(guildID string) store.GuildSettings {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+	return loadSettings(guildID)
+}
+
+// UpdateSettings changes a guild's settings with update, saves them and applies
+// them to the guild's session if it has one.
+func UpdateSettings(guildID string, update func(settings *store.GuildSettings)) (store.GuildSettings, error) {
+	settingsMutex.Lock()
+	settings := loadSettings(guildID)
+	update(&settings)
+
+	if settingsStore != nil {
+		if err := settingsStore.Save(guildID, &settings); err != nil {
+			settingsMutex.Unlock()
+			return settings, fmt.Errorf("error saving settings: %w", err)
+		}
+	}
+	guildSettings[guildID] = settings
+	settingsMutex.Unlock()
+
+	applySettingsToSession(guildID, settings)
+	return settings, nil
+}
+
+// ResetSettings puts a guild's settings back to the defaults.
+func ResetSettings(guildID string) (store.GuildSettings, error) {
+	settingsMutex.Lock()
+	if settingsStore != nil {
+		if err := settingsStore.Delete(guildID); err != nil {
+			settingsMutex.Unlock()
+			return defaultSettings, fmt.Errorf("error resetting settings: %w", err)
+		}
+	}
+	delete(guildSettings, guildID)
+	settings := defaultSettings
+	settingsMutex.Unlock()
+
+	applySettingsToSession(guildID, settings)
+	return settings, nil
+}
+
+// loadSettings returns the cached settings for a guild, reading them from the
+// store the first time. settingsMutex must be held.
+func loadSettings(guildID string) store.GuildSettings {
+	if settings, ok := guildSettings[guildID]; ok {
+		return settings
+	}
+
+	settings := defaultSettings
+	if settingsStore != nil {
+		saved, err := settingsStore.Load(guildID)
+		if err != nil {
+			log.Printf("Error loading settings for guild %s, using defaults: %v", guildID, err)
+		} else if saved != nil {
+			settings = *saved
+		}
+	}
+	guildSettings[guildID] = settings
+	return settings
+}
+
+// applySettingsToSession updates a running session with changed settings. Default
+// volume and loop mode only apply to new sessions so they don't cut into what's playing.
+func applySettingsToSession(guildID string, settings store.GuildSettings) {
+	sessionsMutex.Lock()
+	session, exists := sessions[guildID]
+	sessionsMutex.Unlock()
+	if !exists {
+		return
+	}
+
+	session.Player.SetLimits(limitsFrom(settings))
+	session.mu.Lock()
+	session.TextChannelID = session.announceChannel(settings)
+	session.mu.Unlock()
+}
+
+// announceChannel is where songs are announced, the guild's announce channel if it
+// has one, otherwise the channel the session was started from. s.mu must be held.
+func (s *Session) announceChannel(settings store.GuildSettings) string {
+	if settings.AnnounceChannelID != "" {
+		return settings.AnnounceChannelID
+	}
+	return s.commandChannelID
+}
+
+// limitsFrom picks the queue limits out of a guild's settings
+func limitsFrom(settings store.GuildSettings) player.Limits {
+	return player.Limits{
+		MaxTrackLength:   settings.MaxTrackLength,
+		MaxQueueSize:     settings.MaxQueueSize,
+		MaxTracksPerUser: settings.MaxTracksPerUser,
+	}
+}
@@ -9,15 +9,16 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+// moveTimeout is how long a move to another voice channel has to finish
+const moveTimeout = 10 * time.Second
+
+// sendVoiceStateUpdate asks Discord to move the bot, tests stand in for the gateway
+var sendVoiceStateUpdate = (*discordgo.Session).VoiceStateUpdate
+
 // JoinVoiceChannel finds the voice channel of the user who triggered the interaction and joins it.
 
 func (s *Session) JoinVoiceChannel(i *discordgo.InteractionCreate) error {
 	g, err := s.Session.State.Guild(i.GuildID)
-	// Create a context that automatically cancels after 5 seconds
-    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-
-	defer cancel()
-
 	if err != nil {
 		return fmt.Errorf("could not find guild: %w", err)
 	}
@@ -29,19 +30,154 @@ func (s *Session) JoinVoiceChannel(i *discordgo.InteractionCreate) error {
 	}
 
 	// Join the user's voice channel.
-	vc, err := s.Session.ChannelVoiceJoin(ctx, s.GuildID, vs.ChannelID, false, true)
+	err = s.joinChannel(vs.ChannelID)
 	if err != nil {
 		s.FollowupMessage(i.Interaction, "Error joining voice channel")
+		return err
+	}
+
+	return nil
+}
+
+// joinChannel joins the given voice channel in the session's guild.
+func (s *Session) joinChannel(channelID string) error {
+	// Create a context that automatically cancels after 5 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	vc, err := s.Session.ChannelVoiceJoin(ctx, s.GuildID, channelID, false, true)
+	if err != nil {
 		return fmt.Errorf("could not join voice channel: %w", err)
 	}
 
 	s.mu.Lock()
 	s.VoiceConnection = vc
+	s.VoiceChannelID = channelID
+	s.mu.Unlock()
+
+	// Rejoin if the connection fails while we're using it
+	go s.superviseVoice(vc)
+
+	return nil
+}
+
+// MoveToChannel moves the bot to another voice channel without leaving, so the
+// current song keeps playing. It waits for Discord to confirm the move and the
+// voice connection to be ready again before updating the session.
+func (s *Session) MoveToChannel(channelID string) error {
+	vc := s.GetVoiceConnection()
+	if vc == nil {
+		return fmt.Errorf("not connected to a voice channel")
+	}
+
+	moved := s.expectMove(channelID)
+	defer s.expectMove("")
+
+	if err := sendVoiceStateUpdate(s.Session, s.GuildID, channelID, false, true); err != nil {
+		return fmt.Errorf("could not move to voice channel: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), moveTimeout)
+	defer cancel()
+
+	select {
+	case <-moved:
+	case <-ctx.Done():
+		return fmt.Errorf("timed out moving to voice channel")
+	}
+	if err := waitUntilReady(ctx, vc); err != nil {
+		return fmt.Errorf("voice connection not ready after moving: %w", err)
+	}
+
+	s.mu.Lock()
+	s.VoiceChannelID = channelID
 	s.mu.Unlock()
 
+	// The new channel may have no one in it, or people who stop the bot leaving
+	HandleListenerChange(s.GuildID)
 	return nil
 }
 
+// expectMove records that the bot is being moved to channelID, returning a channel
+// closed once Discord reports the bot there. An empty channelID clears it.
+func (s *Session) expectMove(channelID string) <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.movingTo = channelID
+	s.moved = make(chan struct{})
+	return s.moved
+}
+
+// waitUntilReady blocks until vc is ready to send audio, it fails if the
+// connection dies or ctx ends first.
+func waitUntilReady(ctx context.Context, vc *discordgo.VoiceConnection) error {
+	// Cond can't time out by itself, so wake the wait below when ctx ends
+	stop := context.AfterFunc(ctx, func() {
+		vc.Cond.L.Lock()
+		vc.Cond.Broadcast()
+		vc.Cond.L.Unlock()
+	})
+	defer stop()
+
+	vc.Cond.L.Lock()
+	defer vc.Cond.L.Unlock()
+	for vc.Status != discordgo.VoiceConnectionStatusReady {
+		if vc.Status == discordgo.VoiceConnectionStatusDead {
+			if vc.Err != nil {
+				return vc.Err
+			}
+			return fmt.Errorf("voice connection closed")
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		vc.Cond.Wait()
+	}
+	return nil
+}
+
+// UserVoiceChannelID returns the voice channel a user is in, or "" if they aren't in one.
+func (s *Session) UserVoiceChannelID(userID string) string {
+	g, err := s.Session.State.Guild(s.GuildID)
+	if err != nil {
+		return ""
+	}
+	if vs := findUserVoiceState(g, userID); vs != nil {
+		return vs.ChannelID
+	}
+	return ""
+}
+
+// HandleBotMoved keeps a guild's session in step when the bot changes voice
+// channel, e.g. when a moderator drags it somewhere else.
+func HandleBotMoved(guildID, channelID string) {
+	sessionsMutex.Lock()
+	session, exists := sessions[guildID]
+	sessionsMutex.Unlock()
+	if !exists {
+		return
+	}
+
+	session.mu.Lock()
+	if session.movingTo != "" && session.movingTo == channelID {
+		// MoveToChannel updates the session once the connection is ready
+		close(session.moved)
+		session.movingTo = ""
+		session.mu.Unlock()
+		return
+	}
+	moved := session.VoiceChannelID != "" && session.VoiceChannelID != channelID
+	session.VoiceChannelID = channelID
+	session.mu.Unlock()
+
+	if moved {
+		log.Printf("Bot was moved to voice channel %s in guild: %s", channelID, guildID)
+	}
+
+	// The new channel may have no one in it, or people who stop the bot leaving
+	HandleListenerChange(guildID)
+}
+
 func (s *Session) LeaveVoiceChannel() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -51,6 +187,9 @@ func (s *Session) LeaveVoiceChannel() {
 
 	defer cancel()
 
+	// Leaving on purpose stops any reconnect in progress
+	s.reconnecting = false
+
 	if s.VoiceConnection != nil {
 		err := s.VoiceConnection.Disconnect(ctx)
 		if err != nil {
@@ -59,6 +198,7 @@ func (s *Session) LeaveVoiceChannel() {
     }
 	
 		s.VoiceConnection = nil
+		s.VoiceChannelID = ""
 	}
 }
 
@@ -80,6 +220,41 @@ func findUserVoiceState(guild *discordgo.Guild, userID string) *discordgo.VoiceS
 	return nil
 }
 
+// VoiceListeners returns the IDs of the people in the bot's voice channel, leaving out bots.
+func (s *Session) VoiceListeners() []string {
+	s.mu.RLock()
+	channelID := s.VoiceChannelID
+	s.mu.RUnlock()
+	if channelID == "" {
+		return nil
+	}
+
+	g, err := s.Session.State.Guild(s.GuildID)
+	if err != nil {
+		return nil
+	}
+
+	var listeners []string
+	for _, vs := range g.VoiceStates {
+		if vs.ChannelID == channelID && !s.isBot(vs) {
+			listeners = append(listeners, vs.UserID)
+		}
+	}
+	return listeners
+}
+
+// isBot reports whether a voice state belongs to a bot, including this one.
+func (s *Session) isBot(vs *discordgo.VoiceState) bool {
+	if s.Session.State.User != nil && vs.UserID == s.Session.State.User.ID {
+		return true
+	}
+	member := vs.Member
+	if member == nil || member.User == nil {
+		member, _ = s.Session.State.Member(s.GuildID, vs.UserID)
+	}
+	return member != nil && member.User != nil && member.User.Bot
+}
+
 // IsVoiceConnected checks if the bot is still connected to a voice channel
 func (s *Session) IsVoiceConnected() bool {
 	s.mu.RLock()
@@ -0,0 +1,109 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// newTestVoiceSession registers a session connected to channelID whose voice
+// connection is still coming up.
+func newTestVoiceSession(t *testing.T, guildID, channelID string) (*Session, *discordgo.VoiceConnection) {
+	t.Helper()
+	vc := &discordgo.VoiceConnection{
+		Cond:   sync.NewCond(&sync.Mutex{}),
+		Status: discordgo.VoiceConnectionStatusConnecting,
+	}
+	session := &Session{
+		Session:         &discordgo.Session{State: discordgo.NewState()},
+		GuildID:         guildID,
+		VoiceChannelID:  channelID,
+		VoiceConnection: vc,
+	}
+
+	sessionsMutex.Lock()
+	sessions[guildID] = session
+	sessionsMutex.Unlock()
+	t.Cleanup(func() {
+		sessionsMutex.Lock()
+		delete(sessions, guildID)
+		sessionsMutex.Unlock()
+	})
+	return session, vc
+}
+
+// setStatus changes a voice connection's status the way discordgo does
+func setStatus(vc *discordgo.VoiceConnection, status discordgo.VoiceConnectionStatus, err error) {
+	vc.Cond.L.Lock()
+	vc.Status = status
+	vc.Err = err
+	vc.Cond.Broadcast()
+	vc.Cond.L.Unlock()
+}
+
+// fakeGateway answers voice state updates with respond instead of sending them to Discord
+func fakeGateway(t *testing.T, respond func(guildID, channelID string)) {
+	t.Helper()
+	original := sendVoiceStateUpdate
+	sendVoiceStateUpdate = func(_ *discordgo.Session, guildID, channelID string, _, _ bool) error {
+		go respond(guildID, channelID)
+		return nil
+	}
+	t.Cleanup(func() { sendVoiceStateUpdate = original })
+}
+
+func TestMoveToChannelWaitsForReady(t *testing.T) {
+	session, vc := newTestVoiceSession(t, "guild1", "old")
+	fakeGateway(t, func(guildID, channelID string) {
+		HandleBotMoved(guildID, channelID)
+		if session.GetVoiceChannelID() != "old" {
+			t.Error("Expected the channel to stay the same until the connection is ready")
+		}
+		setStatus(vc, discordgo.VoiceConnectionStatusReady, nil)
+	})
+
+	if err := session.MoveToChannel("new"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if session.GetVoiceChannelID() != "new" {
+		t.Errorf("Expected the session to be in the new channel, got %q", session.GetVoiceChannelID())
+	}
+}
+
+func TestMoveToChannelFailsWhenConnectionDies(t *testing.T) {
+	session, vc := newTestVoiceSession(t, "guild1", "old")
+	fakeGateway(t, func(guildID, channelID string) {
+		HandleBotMoved(guildID, channelID)
+		setStatus(vc, discordgo.VoiceConnectionStatusDead, errors.New("voice server gone"))
+	})
+
+	if err := session.MoveToChannel("new"); err == nil {
+		t.Fatal("Expected an error when the connection dies during the move")
+	}
+	if session.GetVoiceChannelID() != "old" {
+		t.Errorf("Expected the session to stay in the old channel, got %q", session.GetVoiceChannelID())
+	}
+}
+
+func TestHandleBotMovedFollowsModeratorMoves(t *testing.T) {
+	session, _ := newTestVoiceSession(t, "guild1", "old")
+
+	HandleBotMoved("guild1", "elsewhere")
+	if session.GetVoiceChannelID() != "elsewhere" {
+		t.Errorf("Expected the session to follow the bot, got %q", session.GetVoiceChannelID())
+	}
+}
+
+func TestWaitUntilReadyTimesOut(t *testing.T) {
+	_, vc := newTestVoiceSession(t, "guild1", "old")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := waitUntilReady(ctx, vc); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the wait to time out, got %v", err)
+	}
+}
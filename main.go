@@ -1,28 +1,76 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
 	"github.com/coreyo-git/beatgopher/commands"
 	"github.com/coreyo-git/beatgopher/config"
 	"github.com/coreyo-git/beatgopher/discord"
+	"github.com/coreyo-git/beatgopher/player"
+	"github.com/coreyo-git/beatgopher/services"
+	"github.com/coreyo-git/beatgopher/store"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
 	// Create a new Discord session using the provided bot token.
-	session, err := discordgo.New("Bot " + config.Cfg.Token)
+	session, err := discordgo.New("Bot " + cfg.Token)
 	if err != nil {
 		log.Fatalf("Invalid bot parameters: %v", err)
 	}
 
+	// Save guild queues to disk so they can be resumed after a restart
+	queueStore, err := store.NewJSONFileStore(filepath.Join(cfg.DataDir, "queues"))
+	if err != nil {
+		log.Printf("Queue persistence disabled: %v", err)
+	} else {
+		discord.SetQueueStore(queueStore)
+	}
+
+	// Guilds start from these settings until an admin changes them with /settings
+	discord.SetDefaultSettings(store.GuildSettings{
+		DefaultVolume:    cfg.DefaultVolume,
+		IdleTimeout:      cfg.IdleTimeout,
+		MaxTrackLength:   cfg.MaxTrackLength,
+		MaxQueueSize:     cfg.MaxQueueSize,
+		MaxTracksPerUser: cfg.MaxTracksPerUser,
+		LoopDefault:      player.LoopOff.String(),
+		VoteSkipPercent:  cfg.VoteSkipPercent,
+	})
+	settingsStore, err := store.NewJSONSettingsStore(filepath.Join(cfg.DataDir, "settings"))
+	if err != nil {
+		log.Printf("Settings will not be saved: %v", err)
+	} else {
+		discord.SetSettingsStore(settingsStore)
+	}
+
+	// Give each yt-dlp run its own directory so servers don't clean up each other's fragments
+	if err := services.SetWorkDir(filepath.Join(os.TempDir(), "beatgopher-ytdlp")); err != nil {
+		log.Printf("Using the default yt-dlp work directory: %v", err)
+	}
+	services.StartWorkDirSweeper(services.WorkDirSweepInterval)
+
+	// Cancelled on shutdown so searches and streams kill their yt-dlp and ffmpeg processes
+	ctx, cancel := context.WithCancel(context.Background())
+	discord.SetContext(ctx)
+	commands.SetContext(ctx)
+
 	// Add a handler for interactions e.g.. /play
-	session.AddHandler(interactionCreate)
+	session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		interactionCreate(ctx, s, i)
+	})
 
 	// Add a handler for voice state changes to detect disconnections
 	session.AddHandler(voiceStateUpdate)
@@ -47,58 +95,85 @@ func main() {
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-sc
 
+	// Save every guild's queue before leaving so it can be resumed on startup
+	discord.Shutdown()
+
+	// Stop anything still running for interactions or players
+	cancel()
+
 	// Cleanly close down the Discord session.
 	session.Close()
 }
 
 // interactionCreate will be called every time a new interaction is created.
-func interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func interactionCreate(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
 	// Check interaction type
-	if i.Type == discordgo.InteractionApplicationCommand {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
 		// Look for command with matching name in registry
 		if cmd, ok := commands.Commands[i.ApplicationCommandData().Name]; ok {
-
-			// TODO implement context.Context at top level and pass it down to handlers
-			// to allow for better cancellation and timeout handling
-
-			go func() {
-				defer func() {
-					if r := recover(); r != nil {
-						err := s.InteractionRespond(i.Interaction,  
-							&discordgo.InteractionResponse{
-								Type: discordgo.InteractionResponseChannelMessageWithSource,
-								Data: &discordgo.InteractionResponseData{
-									Content: "An error occurred while processing your command.",
-									Flags:   discordgo.MessageFlagsEphemeral, // optional: only visible to the user
-								},
-							})
-						if err != nil {
-							s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-								Content: "An error occurred while processing your command.",
-								Flags:   discordgo.MessageFlagsEphemeral,
-							})
-						}
-
-						log.Printf("Recovered from panic in command %s: %v", i.ApplicationCommandData().Name, r)
-					}
-				}()
-
-				// if exists call relative handler
-				cmd.Handler(s, i)
-			}()
+			handler := commands.Guard(cmd.Permission, cmd.OwnSong, cmd.Handler)
+			go runHandler(ctx, s, i, "command "+i.ApplicationCommandData().Name, handler)
+		}
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		// Suggestions are sent while the user types, so only commands that offer them are routed
+		if cmd, ok := commands.Commands[i.ApplicationCommandData().Name]; ok && cmd.Autocomplete != nil {
+			go runHandler(ctx, s, i, "autocomplete "+i.ApplicationCommandData().Name, cmd.Autocomplete)
+		}
+	case discordgo.InteractionMessageComponent:
+		// Components are routed by the prefix of their custom ID e.g. select menus
+		customID := i.MessageComponentData().CustomID
+		if component, ok := commands.FindComponent(customID); ok {
+			handler := commands.Guard(component.Permission, component.OwnSong, component.Handler)
+			go runHandler(ctx, s, i, "component "+customID, handler)
 		}
 	}
 }
 
+// runHandler calls an interaction handler, letting the user know if it panics.
+// Anything the handler started under ctx is stopped once it returns.
+func runHandler(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, name string, handler commands.HandlerFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.InteractionRespond(i.Interaction,  
+				&discordgo.InteractionResponse{
+					Type: discordgo.InteractionResponseChannelMessageWithSource,
+					Data: &discordgo.InteractionResponseData{
+						Content: "An error occurred while processing your command.",
+						Flags:   discordgo.MessageFlagsEphemeral, // optional: only visible to the user
+					},
+				})
+			if err != nil {
+				s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+					Content: "An error occurred while processing your command.",
+					Flags:   discordgo.MessageFlagsEphemeral,
+				})
+			}
+
+			log.Printf("Recovered from panic in %s: %v", name, r)
+		}
+	}()
+
+	// if exists call relative handler
+	handler(ctx, s, i)
+}
+
 func onReady(s *discordgo.Session, event *discordgo.Ready) {
 	log.Println("Registering commands...")
 	registerCommands(s)
+
+	// Let guilds know if their queue was saved before a restart
+	discord.OfferResume(s)
 }
 
 // voiceStateUpdate handles voice state changes to detect when the bot gets disconnected
 func voiceStateUpdate(s *discordgo.Session, vsu *discordgo.VoiceStateUpdate) {
-	// Check if this is our bot's voice state
+	// Someone else joined or left, the bot leaves if it's on its own for too long
 	if vsu.UserID != s.State.User.ID {
+		discord.HandleListenerChange(vsu.GuildID)
 		return
 	}
 
@@ -106,7 +181,11 @@ func voiceStateUpdate(s *discordgo.Session, vsu *discordgo.VoiceStateUpdate) {
 	if vsu.ChannelID == "" {
 		log.Printf("Bot was disconnected from voice channel in guild: %s", vsu.GuildID)
 		discord.HandleBotDisconnection(vsu.GuildID)
+		return
 	}
+
+	// Otherwise the bot joined or was moved, the player carries on in the new channel
+	discord.HandleBotMoved(vsu.GuildID, vsu.ChannelID)
 }
 
 // Iterates over command registry adding each command
@@ -1,7 +1,11 @@
 package mocks
 
 import (
+	"context"
+	"time"
+
 	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/queue"
 	"github.com/coreyo-git/beatgopher/services"
 )
 
@@ -28,23 +32,57 @@ func (mds *MockDiscordSession) InteractionRespond(i *discordgo.Interaction, cont
 	return nil
 }
 
+func (mds *MockDiscordSession) InteractionRespondEphemeral(i *discordgo.Interaction, content string) error {
+	mds.messages = append(mds.messages, content)
+	return nil
+}
+
+func (mds *MockDiscordSession) InteractionRespondEmbed(i *discordgo.Interaction, embed *discordgo.MessageEmbed) error {
+	mds.embedsSent = append(mds.embedsSent, embed.Title)
+	return nil
+}
+
+func (mds *MockDiscordSession) AutocompleteRespond(i *discordgo.Interaction, choices []*discordgo.ApplicationCommandOptionChoice) error {
+	return nil
+}
+
 func (mds *MockDiscordSession) FollowupMessage(i *discordgo.Interaction, content string) error {
 	mds.messages = append(mds.messages, content)
 	return nil
 }
 
+func (mds *MockDiscordSession) FollowupComponents(i *discordgo.Interaction, content string, components []discordgo.MessageComponent) error {
+	mds.messages = append(mds.messages, content)
+	return nil
+}
+
+func (mds *MockDiscordSession) InteractionUpdate(i *discordgo.Interaction, content string) error {
+	mds.messages = append(mds.messages, content)
+	return nil
+}
+
+func (mds *MockDiscordSession) InteractionUpdateEmbed(i *discordgo.Interaction, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) error {
+	mds.embedsSent = append(mds.embedsSent, embed.Title)
+	return nil
+}
+
 func (mds *MockDiscordSession) SendChannelMessage(message string) error {
 	mds.messages = append(mds.messages, message)
 	return nil
 }
 
-func (mds *MockDiscordSession) SendSongEmbed(song *services.YoutubeResult, footer string) error {
+func (mds *MockDiscordSession) SendSongEmbed(song *queue.Track, footer string) error {
 	mds.embedsSent = append(mds.embedsSent, song.Title+" - "+footer)
 	return nil
 }
 
-func (mds *MockDiscordSession) SendQueueEmbed(songs []*services.YoutubeResult, currentPage int, totalPages int) error {
-	mds.embedsSent = append(mds.embedsSent, "Queue embed sent")
+func (mds *MockDiscordSession) SendNowPlayingEmbed(song *queue.Track, footer string) error {
+	mds.embedsSent = append(mds.embedsSent, song.Title+" - "+footer)
+	return nil
+}
+
+func (mds *MockDiscordSession) InteractionRespondEmbedComponents(i *discordgo.Interaction, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) error {
+	mds.embedsSent = append(mds.embedsSent, embed.Title)
 	return nil
 }
 
@@ -92,7 +130,7 @@ func NewMockYoutubeService() *MockYoutubeService {
 	}
 }
 
-func (mys *MockYoutubeService) GetYoutubeInfo(url string) (services.YoutubeResult, error) {
+func (mys *MockYoutubeService) GetYoutubeInfo(ctx context.Context, url string) (services.YoutubeResult, error) {
 	if result, exists := mys.infoResults[url]; exists {
 		return result, nil
 	}
@@ -102,12 +140,13 @@ func (mys *MockYoutubeService) GetYoutubeInfo(url string) (services.YoutubeResul
 		Channel:   "Test Channel",
 		Title:     "Test Video",
 		Duration:  "3:30",
+		Length:    210 * time.Second,
 		URL:       url,
 		Thumbnail: "test-thumbnail.jpg",
 	}, nil
 }
 
-func (mys *MockYoutubeService) SearchYoutube(query string) (services.YoutubeResult, error) {
+func (mys *MockYoutubeService) SearchYoutube(ctx context.Context, query string) (services.YoutubeResult, error) {
 	if result, exists := mys.searchResults[query]; exists {
 		return result, nil
 	}
@@ -117,12 +156,18 @@ func (mys *MockYoutubeService) SearchYoutube(query string) (services.YoutubeResu
 		Channel:   "Search Test Channel",
 		Title:     "Search Test Video for: " + query,
 		Duration:  "4:15",
+		Length:    255 * time.Second,
 		URL:       "https://youtube.com/watch?v=test",
 		Thumbnail: "search-test-thumbnail.jpg",
 	}, nil
 }
 
-func (mys *MockYoutubeService) GetYoutubePlaylistInfo(playlistURL string, total int64, randomizeSongs bool) ([]services.YoutubeResult, error) {
+func (mys *MockYoutubeService) SearchYoutubeResults(ctx context.Context, query string, limit int) ([]services.YoutubeResult, error) {
+	result, _ := mys.SearchYoutube(ctx, query)
+	return []services.YoutubeResult{result}, nil
+}
+
+func (mys *MockYoutubeService) GetYoutubePlaylistInfo(ctx context.Context, playlistURL string, total int64, randomizeSongs bool) ([]services.YoutubeResult, error) {
 	// Return mock playlist results
 	results := []services.YoutubeResult{
 		{
@@ -130,6 +175,7 @@ func (mys *MockYoutubeService) GetYoutubePlaylistInfo(playlistURL string, total
 			Channel:   "Playlist Channel",
 			Title:     "Playlist Song 1",
 			Duration:  "3:45",
+			Length:    225 * time.Second,
 			URL:       "https://youtube.com/watch?v=playlist1",
 			Thumbnail: "playlist1-thumbnail.jpg",
 		},
@@ -138,6 +184,7 @@ func (mys *MockYoutubeService) GetYoutubePlaylistInfo(playlistURL string, total
 			Channel:   "Playlist Channel",
 			Title:     "Playlist Song 2",
 			Duration:  "4:20",
+			Length:    260 * time.Second,
 			URL:       "https://youtube.com/watch?v=playlist2",
 			Thumbnail: "playlist2-thumbnail.jpg",
 		},
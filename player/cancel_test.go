@@ -0,0 +1,50 @@
+//go:build cgo
+
+package player
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/queue"
+)
+
+func TestCancelEndsPausedStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPlayer(ctx, queue.NewQueue(), nil, nil, nil, nil)
+	vc := &discordgo.VoiceConnection{Cond: sync.NewCond(&sync.Mutex{})}
+
+	done := make(chan streamEnd, 1)
+	go func() {
+		end, _ := waitWhilePaused(p, vc)
+		done <- end
+	}()
+
+	cancel()
+	select {
+	case end := <-done:
+		if end != streamStopped {
+			t.Errorf("Expected the stream to stop, got %v", end)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the paused stream to end once cancelled")
+	}
+}
+
+func TestFailedSeekWhilePausedClearsPause(t *testing.T) {
+	p := NewPlayer(context.Background(), queue.NewQueue(), nil, nil, nil, nil)
+	vc := &discordgo.VoiceConnection{Cond: sync.NewCond(&sync.Mutex{})}
+	p.IsPaused = true
+
+	// Nothing is current, so the stream can't be restarted
+	p.seek <- time.Minute
+	if end, _ := waitWhilePaused(p, vc); end != streamFailed {
+		t.Errorf("Expected the stream to fail, got %v", end)
+	}
+	if p.IsPlayerPaused() {
+		t.Error("Expected the failed stream not to leave the player paused")
+	}
+}
@@ -0,0 +1,118 @@
+package player
+
+import (
+	"log"
+	"time"
+
+	"github.com/coreyo-git/beatgopher/queue"
+)
+
+// historySize is how many played tracks each player remembers.
+const historySize = 50
+
+// HistoryEntry is a track that has been played and when it started.
+type HistoryEntry struct {
+	Track    *queue.Track
+	PlayedAt time.Time
+}
+
+// history is a fixed size ring buffer of played tracks, the oldest are
+// overwritten once it's full. It is guarded by the player's mutex.
+type history struct {
+	entries []HistoryEntry
+	next    int
+	count   int
+}
+
+func newHistory(size int) *history {
+	return &history{
+		entries: make([]HistoryEntry, size),
+	}
+}
+
+// add records an entry, overwriting the oldest if the buffer is full
+func (h *history) add(entry HistoryEntry) {
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+	if h.count < len(h.entries) {
+		h.count++
+	}
+}
+
+// list returns the entries, most recent first
+func (h *history) list() []HistoryEntry {
+	list := make([]HistoryEntry, 0, h.count)
+	for i := 1; i <= h.count; i++ {
+		list = append(list, h.entries[(h.next-i+len(h.entries))%len(h.entries)])
+	}
+	return list
+}
+
+// pop removes and returns the most recent entry
+func (h *history) pop() (HistoryEntry, bool) {
+	if h.count == 0 {
+		return HistoryEntry{}, false
+	}
+	h.next = (h.next - 1 + len(h.entries)) % len(h.entries)
+	entry := h.entries[h.next]
+	h.entries[h.next] = HistoryEntry{}
+	h.count--
+	return entry, true
+}
+
+// History returns the recently played tracks, most recent first.
+func (p *Player) History() []HistoryEntry {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.history.list()
+}
+
+// Previous puts the last played track back at the front of the queue and skips
+// the current one, which is queued straight after it so nothing is lost.
+// It returns false if nothing has been played yet.
+func (p *Player) Previous() (*queue.Track, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.history.pop()
+	if !ok {
+		return nil, false
+	}
+
+	if p.IsPlaying && p.currentSong != nil {
+		p.Queue.InsertAt(0, p.currentSong)
+		p.Queue.InsertAt(0, entry.Track)
+		p.rewinding = true
+
+		// Non-blocking send to skip channel
+		select {
+		case p.skip <- true:
+		default:
+		}
+		return entry.Track, true
+	}
+
+	p.Queue.InsertAt(0, entry.Track)
+	if !p.IsPlaying {
+		log.Printf("Starting playback loop")
+		p.IsPlaying = true
+		go p.playbackLoop()
+	}
+	return entry.Track, true
+}
+
+// recordPlayed adds a finished track to the history
+func (p *Player) recordPlayed(track *queue.Track, playedAt time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.history.add(HistoryEntry{Track: track, PlayedAt: playedAt})
+}
+
+// takeRewinding reports whether the last song ended because of Previous, clearing the flag
+func (p *Player) takeRewinding() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rewinding := p.rewinding
+	p.rewinding = false
+	return rewinding
+}
@@ -0,0 +1,80 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/coreyo-git/beatgopher/queue"
+	"github.com/coreyo-git/beatgopher/services"
+)
+
+func newHistoryTrack(n int) *queue.Track {
+	return queue.NewTrack(&services.YoutubeResult{ID: fmt.Sprintf("song%d", n)}, "", "")
+}
+
+func TestHistoryWrapsAround(t *testing.T) {
+	h := newHistory(3)
+	for n := 1; n <= 5; n++ {
+		h.add(HistoryEntry{Track: newHistoryTrack(n), PlayedAt: time.Now()})
+	}
+
+	list := h.list()
+	expected := []string{"song5", "song4", "song3"}
+	if len(list) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(list))
+	}
+	for i, id := range expected {
+		if list[i].Track.ID != id {
+			t.Errorf("Entry %d: expected %s, got %s", i, id, list[i].Track.ID)
+		}
+	}
+}
+
+func TestHistoryPop(t *testing.T) {
+	h := newHistory(2)
+	if _, ok := h.pop(); ok {
+		t.Error("Expected pop on empty history to fail")
+	}
+
+	for n := 1; n <= 3; n++ {
+		h.add(HistoryEntry{Track: newHistoryTrack(n)})
+	}
+
+	entry, ok := h.pop()
+	if !ok || entry.Track.ID != "song3" {
+		t.Fatalf("Expected to pop song3, got %v", entry.Track)
+	}
+	entry, ok = h.pop()
+	if !ok || entry.Track.ID != "song2" {
+		t.Fatalf("Expected to pop song2, got %v", entry.Track)
+	}
+	if _, ok := h.pop(); ok {
+		t.Error("Expected history to be empty after popping everything")
+	}
+}
+
+func TestPreviousRequeuesCurrentSong(t *testing.T) {
+	q := queue.NewQueue()
+	p := NewPlayer(context.Background(), q, nil, nil, nil, nil)
+	p.IsPlaying = true
+	p.currentSong = newHistoryTrack(2)
+	p.recordPlayed(newHistoryTrack(1), time.Now())
+
+	track, ok := p.Previous()
+	if !ok || track.ID != "song1" {
+		t.Fatalf("Expected to go back to song1, got %v", track)
+	}
+
+	songs := q.GetSongs()
+	if len(songs) != 2 || songs[0].ID != "song1" || songs[1].ID != "song2" {
+		t.Errorf("Expected previous then current at the front of the queue, got %v", songs)
+	}
+	if len(p.History()) != 0 {
+		t.Error("Expected the previous track to be taken out of the history")
+	}
+	if !p.takeRewinding() {
+		t.Error("Expected the player to be rewinding")
+	}
+}
@@ -0,0 +1,52 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coreyo-git/beatgopher/queue"
+)
+
+func TestGoIdleStaysInVoice(t *testing.T) {
+	left := false
+	p := NewPlayer(context.Background(), queue.NewQueue(), nil, nil, nil, func() { left = true })
+	idle := false
+	p.OnIdle = func() { idle = true }
+	p.IsPlaying = true
+
+	if !p.goIdle() {
+		t.Fatal("Expected the player to go idle with an empty queue")
+	}
+	if !idle || left {
+		t.Errorf("Expected OnIdle without leaving, got idle=%v left=%v", idle, left)
+	}
+	if p.IsPlayerPlaying() {
+		t.Error("Expected the player to stop playing")
+	}
+}
+
+func TestGoIdleCarriesOnWhenSongQueued(t *testing.T) {
+	q := queue.NewQueue()
+	p := NewPlayer(context.Background(), q, nil, nil, nil, nil)
+	p.OnIdle = func() { t.Error("Expected OnIdle not to be called") }
+	p.IsPlaying = true
+
+	// A song queued after the loop found the queue empty
+	q.Enqueue(newHistoryTrack(1))
+	if p.goIdle() {
+		t.Error("Expected the loop to carry on")
+	}
+	if !p.IsPlayerPlaying() {
+		t.Error("Expected the player to still be playing")
+	}
+}
+
+func TestGoIdleWithoutCallbackLeaves(t *testing.T) {
+	left := false
+	p := NewPlayer(context.Background(), queue.NewQueue(), nil, nil, nil, func() { left = true })
+	p.IsPlaying = true
+
+	if !p.goIdle() || !left {
+		t.Error("Expected the player to stop and leave")
+	}
+}
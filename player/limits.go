@@ -0,0 +1,84 @@
+package player
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/coreyo-git/beatgopher/queue"
+	"github.com/coreyo-git/beatgopher/services"
+)
+
+// Limits restricts what can be queued in a guild. A zero value turns that limit off.
+// They come from the guild's settings, see store.GuildSettings.
+type Limits struct {
+	// MaxTrackLength is the longest song that can be queued
+	MaxTrackLength time.Duration
+	// MaxQueueSize is the most songs that can wait in the queue
+	MaxQueueSize int
+	// MaxTracksPerUser is the most songs one person can have waiting in the queue
+	MaxTracksPerUser int
+}
+
+// check returns an error explaining why track can't be added to the queued tracks.
+// Songs of unknown length, such as live streams, aren't held to the length limit.
+func (l Limits) check(queued []*queue.Track, track *queue.Track) error {
+	if l.MaxTrackLength > 0 && track.Length > l.MaxTrackLength {
+		return fmt.Errorf("**%s** is %s long, songs can be at most %s", track.Title, services.FormatDuration(track.Length), services.FormatDuration(l.MaxTrackLength))
+	}
+
+	if l.MaxQueueSize > 0 && len(queued) >= l.MaxQueueSize {
+		return fmt.Errorf("the queue is full, it can hold at most %d songs", l.MaxQueueSize)
+	}
+
+	if l.MaxTracksPerUser > 0 && track.RequesterID != "" {
+		count := 0
+		for _, queuedTrack := range queued {
+			if queuedTrack.RequesterID == track.RequesterID {
+				count++
+			}
+		}
+		if count >= l.MaxTracksPerUser {
+			return fmt.Errorf("you already have %d songs in the queue, the limit is %d", count, l.MaxTracksPerUser)
+		}
+	}
+
+	return nil
+}
+
+// SetLimits sets what can be queued.
+func (p *Player) SetLimits(limits Limits) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.limits = limits
+}
+
+// GetLimits returns what can be queued.
+func (p *Player) GetLimits() Limits {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.limits
+}
+
+// CheckLimits returns an error explaining why the user couldn't queue song right now.
+func (p *Player) CheckLimits(i *discordgo.InteractionCreate, song *services.YoutubeResult) error {
+	requesterID, requesterName := requester(i)
+	return p.GetLimits().check(p.Queue.GetSongs(), queue.NewTrack(song, requesterID, requesterName))
+}
+
+// enqueueWithinLimits adds track to the queue, at the front when next is set, if the limits allow it.
+func (p *Player) enqueueWithinLimits(track *queue.Track, next bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.limits.check(p.Queue.GetSongs(), track); err != nil {
+		return err
+	}
+
+	if next {
+		p.Queue.InsertAt(0, track)
+	} else {
+		p.Queue.Enqueue(track)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LoopMode controls what the player does when a song finishes.
+type LoopMode int
+
+const (
+	// LoopOff plays through the queue once
+	LoopOff LoopMode = iota
+	// LoopTrack replays the current song until it is skipped
+	LoopTrack
+	// LoopQueue puts finished songs back on the end of the queue
+	LoopQueue
+)
+
+// String returns the name of the loop mode as used by the /loop command
+func (m LoopMode) String() string {
+	switch m {
+	case LoopTrack:
+		return "track"
+	case LoopQueue:
+		return "queue"
+	default:
+		return "off"
+	}
+}
+
+// Next returns the mode after this one, cycling off → track → queue → off
+func (m LoopMode) Next() LoopMode {
+	switch m {
+	case LoopOff:
+		return LoopTrack
+	case LoopTrack:
+		return LoopQueue
+	default:
+		return LoopOff
+	}
+}
+
+// ParseLoopMode converts a loop mode name back into a LoopMode
+func ParseLoopMode(s string) (LoopMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "off":
+		return LoopOff, nil
+	case "track":
+		return LoopTrack, nil
+	case "queue":
+		return LoopQueue, nil
+	}
+	return LoopOff, fmt.Errorf("unknown loop mode: %s", s)
+}
+
+// SetLoopMode sets what happens when a song finishes.
+func (p *Player) SetLoopMode(mode LoopMode) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loopMode = mode
+}
+
+// GetLoopMode returns the current loop mode.
+func (p *Player) GetLoopMode() LoopMode {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.loopMode
+}
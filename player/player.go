@@ -1,8 +1,12 @@
 package player
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/coreyo-git/beatgopher/queue"
@@ -10,62 +14,236 @@ import (
 )
 
 type PlayerInterface interface {
-	// AddSong adds a song to the queue and starts playback if not already playing
-	AddSong(i *discordgo.InteractionCreate, song *services.YoutubeResult)
+	// AddSong adds a song to the queue and starts playback if not already playing.
+	// It returns an error if the guild's limits don't allow the song.
+	AddSong(i *discordgo.InteractionCreate, song *services.YoutubeResult) error
 
-	// AddSongs adds multiple songs to the queue
-	AddSongs(i *discordgo.InteractionCreate, songs []services.YoutubeResult)
+	// AddSongs adds multiple songs to the queue, skipping any the limits don't allow.
+	// It returns how many were added and an error describing the skipped songs.
+	AddSongs(i *discordgo.InteractionCreate, songs []services.YoutubeResult) (int, error)
+
+	// PlayNext adds a song to the front of the queue and starts playback if not already playing
+	PlayNext(i *discordgo.InteractionCreate, song *services.YoutubeResult) error
+
+	// CheckLimits returns an error explaining why the user can't queue the song
+	CheckLimits(i *discordgo.InteractionCreate, song *services.YoutubeResult) error
+
+	// SetLimits sets what can be queued
+	SetLimits(limits Limits)
+
+	// GetLimits returns what can be queued
+	GetLimits() Limits
+
+	// Restore queues previously saved tracks that are within the limits and starts
+	// playing the first from position. It returns how many tracks were queued
+	Restore(tracks []*queue.Track, position time.Duration) (int, error)
+
+	// History returns the recently played tracks, most recent first
+	History() []HistoryEntry
+
+	// Previous puts the last played track back at the front of the queue and skips the current one
+	Previous() (*queue.Track, bool)
 
 	// Skip skips the current song
 	Skip() bool
 
+	// VoteSkip records a listener's vote to skip the current song, skipping it once there are needed votes
+	VoteSkip(userID string, needed int) SkipVote
+
+	// SkipVotes returns how many listeners have voted to skip the current song
+	SkipVotes() int
+
 	// Stop stops the player and clears the queue
 	Stop()
 
+	// Pause pauses the current song without losing its place
+	Pause() bool
+
+	// Resume resumes a paused song
+	Resume() bool
+
+	// IsPlayerPaused returns true if the player is currently paused
+	IsPlayerPaused() bool
+
+	// Seek jumps to a position in the current song
+	Seek(position time.Duration) error
+
+	// Position returns how far into the current song the player is
+	Position() time.Duration
+
+	// CurrentSong returns the track being played, or nil if nothing is playing
+	CurrentSong() *queue.Track
+
+	// SetLoopMode sets what happens when a song finishes
+	SetLoopMode(mode LoopMode)
+
+	// GetLoopMode returns the current loop mode
+	GetLoopMode() LoopMode
+
+	// SetVolume sets the playback volume as a percentage
+	SetVolume(percent int) error
+
+	// GetVolume returns the playback volume as a percentage
+	GetVolume() int
+
 	// IsPlayerPlaying returns true if the player is currently playing
 	IsPlayerPlaying() bool
 }
 
+// frameDuration is the length of audio in a single Opus frame sent to Discord.
+const frameDuration = 20 * time.Millisecond
+
+// voiceReconnectWait is how long a stream waits for the voice connection to come
+// back, e.g. when the bot is moved to another channel or is reconnecting after the
+// connection failed, before giving up on the song.
+const voiceReconnectWait = time.Minute
+
+// streamEnd describes why a song's stream ended.
+type streamEnd int
+
+const (
+	streamFinished streamEnd = iota // the song played to the end
+	streamSkipped                   // the song was skipped
+	streamStopped                   // the player was stopped
+	streamFailed                    // the stream or voice connection broke
+)
+
 // Player represents a music player for a single guild.
 type Player struct {
 	CurrentStream *services.AudioStream
 	Queue         queue.QueueInterface
 	IsPlaying     bool
+	IsPaused      bool
+	currentSong   *queue.Track
+	startAt       time.Duration
+	history       *history
+	limits        Limits
+	rewinding     bool
+	votes         skipVotes
+	loopMode      LoopMode
+	volume        atomic.Int32
+	framesSent    atomic.Int64
 	stop          chan bool
 	skip          chan bool
+	pause         chan bool
+	resume        chan bool
+	seek          chan time.Duration
 	mu            sync.RWMutex
 
-	OnSendEmbedMessage     func(song *services.YoutubeResult, content string) error
+	// ctx ends playback and kills any yt-dlp or ffmpeg the player started when cancelled
+	ctx context.Context
+
+	OnSendEmbedMessage     func(track *queue.Track, content string) error
 	OnCheckVoiceConnection func() bool
 	OnGetVoiceConnection   func() *discordgo.VoiceConnection
 	OnLeaveVoiceChannel    func()
+
+	// OnTrackStart is optional and called whenever a new track starts playing
+	OnTrackStart func(track *queue.Track)
+
+	// OnSendNowPlaying is optional and sends the "Playing!" embed in place of OnSendEmbedMessage
+	OnSendNowPlaying func(track *queue.Track, content string) error
+
+	// OnIdle is optional and called when the queue runs out, the player stays in the
+	// voice channel until stopped. Without it the player stops and leaves straight away.
+	OnIdle func()
 }
 
 func NewPlayer(
+	ctx context.Context,
 	queue queue.QueueInterface,
-	onSendEmbedMessage func(song *services.YoutubeResult, content string) error,
+	onSendEmbedMessage func(track *queue.Track, content string) error,
 	onCheckVoiceConnection func() bool,
 	onGetVoiceConnection func() *discordgo.VoiceConnection,
 	onLeaveVoiceChannel func(),
 ) *Player {
-	return &Player{
+	player := &Player{
 		CurrentStream: nil,
 		Queue:         queue,
 		IsPlaying:     false,
+		IsPaused:      false,
+		loopMode:      LoopOff,
+		history:       newHistory(historySize),
 		stop:          make(chan bool, 1),
 		skip:          make(chan bool, 1),
+		pause:         make(chan bool, 1),
+		resume:        make(chan bool, 1),
+		seek:          make(chan time.Duration, 1),
 		mu:            sync.RWMutex{},
+		ctx:           ctx,
 
 		OnSendEmbedMessage:     onSendEmbedMessage,
 		OnCheckVoiceConnection: onCheckVoiceConnection,
 		OnGetVoiceConnection:   onGetVoiceConnection,
 		OnLeaveVoiceChannel:    onLeaveVoiceChannel,
 	}
+	player.volume.Store(DefaultVolume)
+
+	return player
 }
 
 // Adds a song to the queue and starts playback if the player is not already playing.
-func (p *Player) AddSong(i *discordgo.InteractionCreate, song *services.YoutubeResult) {
-	p.Queue.Enqueue(song)
+func (p *Player) AddSong(i *discordgo.InteractionCreate, song *services.YoutubeResult) error {
+	requesterID, requesterName := requester(i)
+	track := queue.NewTrack(song, requesterID, requesterName)
+	if err := p.enqueueWithinLimits(track, false); err != nil {
+		return err
+	}
+	p.startOrAnnounce(track, "Added to queue!")
+	return nil
+}
+
+// Adds a song to the front of the queue and starts playback if the player is not already playing.
+func (p *Player) PlayNext(i *discordgo.InteractionCreate, song *services.YoutubeResult) error {
+	requesterID, requesterName := requester(i)
+	track := queue.NewTrack(song, requesterID, requesterName)
+	if err := p.enqueueWithinLimits(track, true); err != nil {
+		return err
+	}
+	p.startOrAnnounce(track, "Playing next!")
+	return nil
+}
+
+// Restore queues tracks saved before a restart and starts playing, picking the
+// first track up from position. Tracks the limits no longer allow, e.g. after an
+// admin lowered them, are left out.
+func (p *Player) Restore(tracks []*queue.Track, position time.Duration) (int, error) {
+	restored := 0
+	skipped := 0
+	var firstErr error
+	for j, track := range tracks {
+		if err := p.enqueueWithinLimits(track, false); err != nil {
+			// The position belongs to the first track, not whichever plays instead
+			if j == 0 {
+				position = 0
+			}
+			skipped++
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		restored++
+	}
+
+	p.mu.Lock()
+	if !p.IsPlaying && restored > 0 {
+		log.Printf("Restoring playback of %d tracks from %v", restored, position)
+		p.startAt = position
+		p.IsPlaying = true
+		go p.playbackLoop()
+	}
+	p.mu.Unlock()
+
+	if skipped > 0 {
+		return restored, fmt.Errorf("skipped %d song(s), %w", skipped, firstErr)
+	}
+	return restored, nil
+}
+
+// startOrAnnounce starts the playback loop for a newly queued track,
+// or lets the channel know it was queued if something is already playing.
+func (p *Player) startOrAnnounce(track *queue.Track, footer string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	if !p.IsPlaying {
@@ -73,52 +251,165 @@ func (p *Player) AddSong(i *discordgo.InteractionCreate, song *services.YoutubeR
 		p.IsPlaying = true
 		go p.playbackLoop()
 	} else {
-		p.OnSendEmbedMessage(song, "Added to queue!")
+		p.OnSendEmbedMessage(track, footer)
 	}
 }
 
-func (p *Player) AddSongs(i *discordgo.InteractionCreate, songs []services.YoutubeResult) {
+func (p *Player) AddSongs(i *discordgo.InteractionCreate, songs []services.YoutubeResult) (int, error) {
+	requesterID, requesterName := requester(i)
+	added := 0
+	skipped := 0
+	var firstErr error
 	for j := 0; j < len(songs); j++ {
-		if j == 0 {
-			p.AddSong(i, &songs[j])
+		track := queue.NewTrack(&songs[j], requesterID, requesterName)
+		if err := p.enqueueWithinLimits(track, false); err != nil {
+			skipped++
+			if firstErr == nil {
+				firstErr = err
+			}
 			continue
 		}
 		log.Printf("Adding song to queue: %v", &songs[j])
-		p.Queue.Enqueue(&songs[j])
+
+		// Only the first song is announced, or starts playback
+		if added == 0 {
+			p.startOrAnnounce(track, "Added to queue!")
+		}
+		added++
+	}
+
+	if skipped > 0 {
+		return added, fmt.Errorf("skipped %d song(s), %w", skipped, firstErr)
 	}
+	return added, nil
 }
 
 // playbackLoop is the main loop for playing songs from the queue.
 // It runs in its own goroutine.
 func (p *Player) playbackLoop() {
+	// song is only carried over to the next iteration when it should be replayed
+	var song *queue.Track
 	for {
 		select {
 		case <-p.stop:
 			return
+		case <-p.ctx.Done():
+			return
 		default:
-			song := p.Queue.Dequeue()
 			if song == nil {
-				p.Stop()
-				return
+				song = p.Queue.Dequeue()
+				if song == nil {
+					if p.goIdle() {
+						return
+					}
+					continue
+				}
+				p.announceNowPlaying(song)
 			}
 
-			p.OnSendEmbedMessage(song, "Playing!")
+			p.dropStaleControls()
 
-			_, err := setupAudioOutput(song, p)
+			offset := p.takeStartAt()
+			p.setCurrentSong(song)
+			startedAt := time.Now()
+
+			_, err := setupAudioOutput(song.YoutubeResult, p, offset)
 			if err != nil {
 				log.Printf("Error in setupAudioOutput: %v", err)
+				p.setCurrentSong(nil)
+				song = nil
 				continue // Skip this song and move to the next one
 			}
+			p.framesSent.Store(int64(offset / frameDuration))
+
+			if p.OnTrackStart != nil {
+				p.OnTrackStart(song)
+			}
 
 			
 			log.Printf("Starting stream.")
-			select {
-			case <-p.skip:
-			default:
+			end := stream(p)
+			p.setCurrentSong(nil)
+
+			// Previous already put this song back in the queue
+			if p.takeRewinding() {
+				song = nil
+				continue
+			}
+
+			if end == streamStopped {
+				p.recordPlayed(song, startedAt)
+				return
 			}
-			stream(p)
+
+			next := p.nextAfter(song, end)
+			if next == nil {
+				p.recordPlayed(song, startedAt)
+			}
+			song = next
+		}
+	}
+}
+
+// goIdle stops the player once the queue has run out. It returns false if a
+// song was queued in the meantime and the loop should carry on.
+func (p *Player) goIdle() bool {
+	if p.OnIdle == nil {
+		// The loop is exiting itself, so Stop has nothing to signal
+		p.mu.Lock()
+		p.IsPlaying = false
+		p.mu.Unlock()
+		p.Stop()
+		return true
+	}
+
+	p.mu.Lock()
+	if p.Queue.Size() > 0 {
+		p.mu.Unlock()
+		return false
+	}
+	p.IsPlaying = false
+	p.IsPaused = false
+	p.currentSong = nil
+	p.startAt = 0
+	p.mu.Unlock()
+
+	p.OnIdle()
+	return true
+}
+
+// nextAfter applies the loop mode once a song's stream has ended.
+// It returns the song if it should be replayed, otherwise nil.
+func (p *Player) nextAfter(song *queue.Track, end streamEnd) *queue.Track {
+	switch p.GetLoopMode() {
+	case LoopTrack:
+		// Skipping moves on even when looping the track
+		if end == streamFinished {
+			return song
+		}
+	case LoopQueue:
+		if end == streamFinished || end == streamSkipped {
+			p.Queue.Enqueue(song)
 		}
 	}
+	return nil
+}
+
+// announceNowPlaying sends the "Playing!" embed for a song that is about to start
+func (p *Player) announceNowPlaying(song *queue.Track) {
+	send := p.OnSendEmbedMessage
+	if p.OnSendNowPlaying != nil {
+		send = p.OnSendNowPlaying
+	}
+	send(song, NowPlayingFooter(p.GetLoopMode()))
+}
+
+// NowPlayingFooter is the footer of the "Playing!" embed, showing the loop mode when it's on.
+func NowPlayingFooter(mode LoopMode) string {
+	if mode == LoopOff {
+		return "Playing!"
+	}
+	return fmt.Sprintf("Playing! • Loop: %s", mode)
 }
 
 // Skip current song.
@@ -141,20 +432,158 @@ func (p *Player) Stop() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	// Clear the queue
-	p.Queue = queue.NewQueue()
+	// IsPlaying is only set while the playback loop is running
+	running := p.IsPlaying
+
+	// Clear the queue, keeping the same queue so anything else holding it sees the change
+	p.Queue.Clear()
 	p.IsPlaying = false
+	p.IsPaused = false
+	p.currentSong = nil
+	p.startAt = 0
+	p.rewinding = false
 
-	// Non-blocking send to stop channel - if nothing is receiving,
-	// the playback loop has already exited, so we just move on
+	// Drop any pending pause/resume so the next song doesn't start paused
+	select {
+	case <-p.pause:
+	default:
+	}
+	select {
+	case <-p.resume:
+	default:
+	}
 	select {
-	case p.stop <- true:
+	case <-p.seek:
 	default:
 	}
 
+	// Only a running loop reads the stop, one left behind would end the next loop straight away
+	if running {
+		select {
+		case p.stop <- true:
+		default:
+		}
+	}
+
 	p.OnLeaveVoiceChannel()
 }
 
+// Pause pauses the current song, the stream holds its place until resumed.
+func (p *Player) Pause() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.IsPlaying || p.IsPaused {
+		return false
+	}
+	p.IsPaused = true
+
+	// Non-blocking send to pause channel
+	select {
+	case p.pause <- true:
+	default:
+	}
+	return true
+}
+
+// Resume resumes a paused song.
+func (p *Player) Resume() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.IsPlaying || !p.IsPaused {
+		return false
+	}
+	p.IsPaused = false
+
+	// Non-blocking send to resume channel
+	select {
+	case p.resume <- true:
+	default:
+	}
+	return true
+}
+
+// IsPlayerPaused returns true if the player is currently paused
+func (p *Player) IsPlayerPaused() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.IsPaused
+}
+
+// Seek jumps to position in the current song by restarting the stream at that offset.
+func (p *Player) Seek(position time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.IsPlaying || p.currentSong == nil {
+		return fmt.Errorf("nothing is playing")
+	}
+
+	if position < 0 {
+		position = 0
+	}
+	if length := p.currentSong.Length; length > 0 && position >= length {
+		return fmt.Errorf("%s is past the end of the song (%s)", services.FormatDuration(position), p.currentSong.Duration)
+	}
+
+	// Replace any seek the stream hasn't picked up yet
+	select {
+	case <-p.seek:
+	default:
+	}
+	p.seek <- position
+	return nil
+}
+
+// Position returns how far into the current song the player is, based on the frames sent.
+func (p *Player) Position() time.Duration {
+	return time.Duration(p.framesSent.Load()) * frameDuration
+}
+
+// CurrentSong returns the song being played, or nil if nothing is playing.
+func (p *Player) CurrentSong() *queue.Track {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.currentSong
+}
+
+// takeStartAt returns the offset the next song should start from, only the
+// first song after a restore starts part way through.
+func (p *Player) takeStartAt() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	offset := p.startAt
+	p.startAt = 0
+	return offset
+}
+
+// dropStaleControls drops skips, seeks and pauses left over from the last song
+// before the next one becomes current, so it doesn't start skipped or paused.
+func (p *Player) dropStaleControls() {
+	select {
+	case <-p.skip:
+	default:
+	}
+	select {
+	case <-p.seek:
+	default:
+	}
+	select {
+	case <-p.pause:
+		p.mu.Lock()
+		p.IsPaused = false
+		p.mu.Unlock()
+	default:
+	}
+}
+
+// setCurrentSong sets the song being streamed and resets the position
+func (p *Player) setCurrentSong(song *queue.Track) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.currentSong = song
+	p.framesSent.Store(0)
+	p.votes = skipVotes{}
+}
+
 // IsPlayerPlaying returns true if the player is currently playing
 func (p *Player) IsPlayerPlaying() bool {
 	p.mu.RLock()
@@ -172,6 +601,20 @@ func (p *Player) cleanupCurrentStream() {
 	}
 }
 
+// requester returns the ID and display name of the user behind an interaction
+func requester(i *discordgo.InteractionCreate) (string, string) {
+	if i == nil || i.Interaction == nil {
+		return "", ""
+	}
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID, i.Member.DisplayName()
+	}
+	if i.User != nil {
+		return i.User.ID, i.User.DisplayName()
+	}
+	return "", ""
+}
+
 // GetQueue returns the queue interface
 func (p *Player) GetQueue() queue.QueueInterface {
 	return p.Queue
@@ -10,28 +10,26 @@ import(
 	"log"
 	"layeh.com/gopus"
 
+	"github.com/bwmarrin/discordgo"
 	"github.com/coreyo-git/beatgopher/services"
 )
 
-// Streams the audio to the voice channel.
-func stream(p *Player) {
+// Streams the audio to the voice channel, returning why the stream ended.
+func stream(p *Player) streamEnd {
 	// Ensure processes are killed when stream exits for any reason
 	defer p.cleanupCurrentStream()
 
 	vc := p.OnGetVoiceConnection()
-	if vc == nil || !p.OnCheckVoiceConnection() {
+	if vc == nil {
 		log.Println("Voice connection is invalid or disconnected, aborting stream")
-		return
+		return streamFailed
 	}
 
-	if vc.Status != 3 {
-		log.Println("Voice connection not ready, waiting...")
-		time.Sleep(100 * time.Millisecond) // Increased wait time
-
-		// Check again after waiting
-		if !p.OnCheckVoiceConnection() {
+	if !p.OnCheckVoiceConnection() {
+		// The connection may be moving to another channel
+		if vc = waitForVoice(p); vc == nil {
 			log.Println("Voice connection lost while waiting, aborting stream")
-			return
+			return streamFailed
 		}
 	}
 
@@ -48,7 +46,7 @@ func stream(p *Player) {
 	encoder, err := gopus.NewEncoder(frameRate, channels, gopus.Audio)
 	if err != nil {
 		log.Printf("Error creating Opus encoder: %v", err)
-		return
+		return streamFailed
 	}
 
 	// Debugging counters
@@ -74,47 +72,86 @@ func stream(p *Player) {
 	// Reads raw PCM data from the stream
 	pcm := make([]int16, frameSize*channels)
 	for {
+		// Hold our place in the PCM pipe while paused
+		select {
+		case <-p.pause:
+			if end, resumed := waitWhilePaused(p, vc); !resumed {
+				return end
+			}
+		case position := <-p.seek:
+			if !restartAt(p, position) {
+				return streamFailed
+			}
+		case <-p.ctx.Done():
+			log.Println("Playback cancelled")
+			return streamStopped
+		default:
+		}
+
 		// read full frame (EOF/error will be returned when stream is closed during cleanup)
 		err := binary.Read(p.CurrentStream.Stdout, binary.LittleEndian, &pcm)
 		if err != nil {
+			// Cancelling kills ffmpeg too, which mustn't count as the song finishing
+			if p.ctx.Err() != nil {
+				log.Printf("Playback cancelled after %d frames", framesProcessed)
+				return streamStopped
+			}
 			if err == io.EOF || err == io.ErrUnexpectedEOF {
 				log.Printf("Stream finished after %d frames", framesProcessed)
-				return
+				return streamFinished
 			}
 			log.Printf("Error reading from audio stream: %v", err)
 			errors++
-			return
+			return streamFailed
 		}
 
+		// Apply the volume before encoding, read every frame so changes take effect mid-song
+		applyVolume(pcm, int(p.volume.Load()))
+
 		// Encode the PCM data into an Opus packet.
 		opus, err := encoder.Encode(pcm, frameSize, maxBytes)
 		if err != nil {
 			log.Printf("Error encoding audio to opus: %v", err)
 			errors++
-			return
+			return streamFailed
+		}
+
+		// A dead connection closes OpusSend, wait for it to be replaced rather than sending to it
+		select {
+		case <-vc.Dead:
+			if vc = waitForVoice(p); vc == nil {
+				log.Println("Voice connection lost, stopping playback")
+				return streamFailed
+			}
+		default:
 		}
 
 		select {
 		case vc.OpusSend <- opus:
 			framesProcessed++
+			p.framesSent.Add(1)
 			// Periodically check if we're still connected (every 100 frames)
 			if framesProcessed%100 == 0 && !p.OnCheckVoiceConnection() {
-				log.Println("Voice connection lost during streaming, stopping playback")
-				return
+				if vc = waitForVoice(p); vc == nil {
+					log.Println("Voice connection lost during streaming, stopping playback")
+					return streamFailed
+				}
 			}
 		case <-p.stop:
 			log.Println("Playback stopped by user")
-			return
+			return streamStopped
 		case <-p.skip:
 			log.Println("Song skipped by user")
-			return
+			return streamSkipped
 		case <-time.After(5 * time.Second):
 			log.Printf("Timeout sending opus packet (frame %d)", framesProcessed)
 			timeouts++
 			// Check if connection is still valid on timeout
 			if !p.OnCheckVoiceConnection() {
-				log.Println("Voice connection lost during timeout, stopping playback")
-				return
+				if vc = waitForVoice(p); vc == nil {
+					log.Println("Voice connection lost during timeout, stopping playback")
+					return streamFailed
+				}
 			}
 			// Don't return, try to recover
 			continue
@@ -122,13 +159,105 @@ func stream(p *Player) {
 	}
 }
 
-// Sets up audio output from a YouTube result.
-func setupAudioOutput(result *services.YoutubeResult, p *Player) (io.ReadCloser, error) {
+// Waits for the voice connection to be ready again, e.g. while the bot is moved
+// to another channel or reconnects. The stream holds its place in the meantime.
+// Returns nil if it doesn't come back in time or the player is stopped.
+func waitForVoice(p *Player) *discordgo.VoiceConnection {
+	log.Println("Voice connection not ready, waiting for it to reconnect...")
+	deadline := time.After(voiceReconnectWait)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !p.OnCheckVoiceConnection() {
+		select {
+		case <-p.stop:
+			// Put the stop back for the playback loop
+			select {
+			case p.stop <- true:
+			default:
+			}
+			return nil
+		case <-deadline:
+			return nil
+		case <-ticker.C:
+		}
+	}
+
+	vc := p.OnGetVoiceConnection()
+	if vc != nil {
+		vc.Speaking(true)
+	}
+	return vc
+}
+
+// Blocks until the player is resumed, returns false along with why the stream
+// should end if the song was stopped or skipped while paused.
+func waitWhilePaused(p *Player, vc *discordgo.VoiceConnection) (streamEnd, bool) {
+	log.Println("Playback paused by user")
+	vc.Speaking(false)
+
+	for {
+		select {
+		case <-p.resume:
+			// A quick pause/resume/pause can leave a stale resume behind
+			if p.IsPlayerPaused() {
+				continue
+			}
+			log.Println("Playback resumed by user")
+			vc.Speaking(true)
+			return streamFinished, true
+		case <-p.stop:
+			log.Println("Playback stopped by user while paused")
+			return streamStopped, false
+		case <-p.skip:
+			log.Println("Song skipped by user while paused")
+			p.mu.Lock()
+			p.IsPaused = false
+			p.mu.Unlock()
+			return streamSkipped, false
+		case position := <-p.seek:
+			if !restartAt(p, position) {
+				p.mu.Lock()
+				p.IsPaused = false
+				p.mu.Unlock()
+				return streamFailed, false
+			}
+		case <-p.ctx.Done():
+			log.Println("Playback cancelled while paused")
+			return streamStopped, false
+		}
+	}
+}
+
+// Replaces the current stream with one starting at position in the same song.
+func restartAt(p *Player, position time.Duration) bool {
+	p.mu.RLock()
+	song := p.currentSong
+	p.mu.RUnlock()
+	if song == nil {
+		return false
+	}
+
+	log.Printf("Seeking to %v in: %s", position, song.Title)
+	p.cleanupCurrentStream()
+
+	_, err := setupAudioOutput(song.YoutubeResult, p, position)
+	if err != nil {
+		log.Printf("Error restarting stream for seek: %v", err)
+		return false
+	}
+
+	p.framesSent.Store(int64(position / frameDuration))
+	return true
+}
+
+// Sets up audio output from a YouTube result, starting offset into the song.
+func setupAudioOutput(result *services.YoutubeResult, p *Player, offset time.Duration) (io.ReadCloser, error) {
 	// Consumer/Producer pipe to buffer to stream
 	pipeReader, pipeWriter := io.Pipe()
 
 	log.Printf("Starting audio stream for: %s", result.Title)
-	CurrentStream, err := services.NewAudioStream(result.URL)
+	CurrentStream, err := services.NewAudioStream(p.ctx, result.URL, offset)
 	if err != nil {
 		log.Printf("Error creating audio stream: %v", err)
 		pipeWriter.CloseWithError(err)
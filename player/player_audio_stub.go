@@ -5,12 +5,15 @@ package player
 import(
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/coreyo-git/beatgopher/services"
 )
 
-func stream(p *Player) {}
+func stream(p *Player) streamEnd {
+	return streamFailed
+}
 
-func setupAudioOutput(result *services.YoutubeResult, p *Player) (io.ReadCloser, error) {
+func setupAudioOutput(result *services.YoutubeResult, p *Player, offset time.Duration) (io.ReadCloser, error) {
 	return nil, fmt.Errorf("audio unavailable: CGO required")
 }
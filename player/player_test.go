@@ -1,7 +1,10 @@
 package player_test
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/coreyo-git/beatgopher/queue"
@@ -12,7 +15,7 @@ import (
 // createTestPlayer creates a Player with dependencies
 func createTestPlayer(q queue.QueueInterface) *player.Player {
 
-	return player.NewPlayer(q, func(song *services.YoutubeResult, content string) error {
+	return player.NewPlayer(context.Background(), q, func(song *queue.Track, content string) error {
 		return nil
 	},
 		func() bool {
@@ -162,7 +165,7 @@ func TestPlayerStop(t *testing.T) {
 	// Track if OnLeaveVoiceChannel was called
 	leaveChannelCalled := false
 
-	player := player.NewPlayer(q, func(song *services.YoutubeResult, content string) error {
+	player := player.NewPlayer(context.Background(), q, func(song *queue.Track, content string) error {
 		return nil
 	},
 		func() bool {
@@ -178,8 +181,8 @@ func TestPlayerStop(t *testing.T) {
 	// Add some test songs to the queue
 	testSong1 := &services.YoutubeResult{ID: "song1", Title: "Song 1"}
 	testSong2 := &services.YoutubeResult{ID: "song2", Title: "Song 2"}
-	q.Enqueue(testSong1)
-	q.Enqueue(testSong2)
+	q.Enqueue(queue.NewTrack(testSong1, "user1", "User 1"))
+	q.Enqueue(queue.NewTrack(testSong2, "user2", "User 2"))
 
 	// Verify queue has songs before stopping
 	if q.Size() != 2 {
@@ -237,8 +240,9 @@ func TestNewPlayer(t *testing.T) {
 	checkVoiceCalled := false
 
 	player := player.NewPlayer(
+		context.Background(),
 		q,
-		func(song *services.YoutubeResult, content string) error {
+		func(song *queue.Track, content string) error {
 			sendEmbedCalled = true
 			return nil
 		},
@@ -266,7 +270,7 @@ func TestNewPlayer(t *testing.T) {
 	}
 
 	// Verify callbacks are wired correctly
-	player.OnSendEmbedMessage(&services.YoutubeResult{}, "test")
+	player.OnSendEmbedMessage(queue.NewTrack(&services.YoutubeResult{}, "", ""), "test")
 	if !sendEmbedCalled {
 		t.Error("Expected OnSendEmbedMessage callback to be called")
 	}
@@ -276,3 +280,415 @@ func TestNewPlayer(t *testing.T) {
 		t.Error("Expected OnCheckVoiceConnection callback to be called")
 	}
 }
+
+func TestPlayerPauseResume(t *testing.T) {
+	q := queue.NewQueue()
+	player := createTestPlayer(q)
+
+	// Test pausing when not playing
+	if player.Pause() {
+		t.Error("Expected Pause() to return false when not playing")
+	}
+
+	player.IsPlaying = true
+
+	// Test resuming when not paused
+	if player.Resume() {
+		t.Error("Expected Resume() to return false when not paused")
+	}
+
+	if !player.Pause() {
+		t.Error("Expected Pause() to return true when playing")
+	}
+	if !player.IsPlayerPaused() {
+		t.Error("Expected IsPlayerPaused() to return true after Pause()")
+	}
+
+	// Test pausing twice
+	if player.Pause() {
+		t.Error("Expected Pause() to return false when already paused")
+	}
+
+	if !player.Resume() {
+		t.Error("Expected Resume() to return true when paused")
+	}
+	if player.IsPlayerPaused() {
+		t.Error("Expected IsPlayerPaused() to return false after Resume()")
+	}
+}
+
+func TestPlayerStopClearsPause(t *testing.T) {
+	q := queue.NewQueue()
+	player := createTestPlayer(q)
+	player.IsPlaying = true
+
+	player.Pause()
+	player.Stop()
+
+	if player.IsPlayerPaused() {
+		t.Error("Expected IsPlayerPaused() to return false after Stop()")
+	}
+}
+
+func TestPlayerSeekWhenNotPlaying(t *testing.T) {
+	q := queue.NewQueue()
+	player := createTestPlayer(q)
+
+	if err := player.Seek(30 * time.Second); err == nil {
+		t.Error("Expected Seek() to return an error when not playing")
+	}
+
+	if player.Position() != 0 {
+		t.Errorf("Expected Position() to be 0 when not playing, got %v", player.Position())
+	}
+}
+
+func TestPlayerLoopMode(t *testing.T) {
+	q := queue.NewQueue()
+	p := createTestPlayer(q)
+
+	if p.GetLoopMode() != player.LoopOff {
+		t.Errorf("Expected loop mode to be off initially, got %s", p.GetLoopMode())
+	}
+
+	p.SetLoopMode(player.LoopQueue)
+	if p.GetLoopMode() != player.LoopQueue {
+		t.Errorf("Expected loop mode to be queue, got %s", p.GetLoopMode())
+	}
+}
+
+func TestParseLoopMode(t *testing.T) {
+	for _, mode := range []player.LoopMode{player.LoopOff, player.LoopTrack, player.LoopQueue} {
+		parsed, err := player.ParseLoopMode(mode.String())
+		if err != nil {
+			t.Errorf("Unexpected error parsing %s: %v", mode, err)
+		}
+		if parsed != mode {
+			t.Errorf("Expected %s, got %s", mode, parsed)
+		}
+	}
+
+	if _, err := player.ParseLoopMode("forever"); err == nil {
+		t.Error("Expected an error for an unknown loop mode")
+	}
+}
+
+func TestLoopModeNextCycles(t *testing.T) {
+	mode := player.LoopOff
+	for _, expected := range []player.LoopMode{player.LoopTrack, player.LoopQueue, player.LoopOff} {
+		mode = mode.Next()
+		if mode != expected {
+			t.Errorf("Expected %s, got %s", expected, mode)
+		}
+	}
+}
+
+func TestNowPlayingFooter(t *testing.T) {
+	if footer := player.NowPlayingFooter(player.LoopOff); footer != "Playing!" {
+		t.Errorf("Expected plain footer with loop off, got %q", footer)
+	}
+	if footer := player.NowPlayingFooter(player.LoopQueue); footer != "Playing! • Loop: queue" {
+		t.Errorf("Expected footer to show the loop mode, got %q", footer)
+	}
+}
+
+func TestPlayerVolume(t *testing.T) {
+	q := queue.NewQueue()
+	p := createTestPlayer(q)
+
+	if p.GetVolume() != player.DefaultVolume {
+		t.Errorf("Expected default volume %d, got %d", player.DefaultVolume, p.GetVolume())
+	}
+
+	if err := p.SetVolume(150); err != nil {
+		t.Errorf("Unexpected error setting volume: %v", err)
+	}
+	if p.GetVolume() != 150 {
+		t.Errorf("Expected volume 150, got %d", p.GetVolume())
+	}
+
+	// Out of range volumes are rejected and leave the volume alone
+	if err := p.SetVolume(player.MaxVolume + 1); err == nil {
+		t.Error("Expected an error for a volume above the maximum")
+	}
+	if err := p.SetVolume(-1); err == nil {
+		t.Error("Expected an error for a negative volume")
+	}
+	if p.GetVolume() != 150 {
+		t.Errorf("Expected volume to stay at 150, got %d", p.GetVolume())
+	}
+}
+
+func TestPlayerCurrentSongWhenIdle(t *testing.T) {
+	q := queue.NewQueue()
+	p := createTestPlayer(q)
+
+	if p.CurrentSong() != nil {
+		t.Error("Expected CurrentSong() to be nil when nothing is playing")
+	}
+}
+
+func TestPlayerAddSongRecordsRequester(t *testing.T) {
+	q := queue.NewQueue()
+	player := createTestPlayer(q)
+	player.IsPlaying = true
+
+	interaction := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID: "test-interaction",
+			Member: &discordgo.Member{
+				Nick: "DJ Gopher",
+				User: &discordgo.User{ID: "user-123", Username: "gopher"},
+			},
+		},
+	}
+
+	player.AddSong(interaction, &services.YoutubeResult{ID: "song1", Title: "Song 1"})
+
+	queuedSong := q.Peek()
+	if queuedSong == nil {
+		t.Fatal("Expected a song in the queue, got nil")
+	}
+
+	if queuedSong.RequesterID != "user-123" {
+		t.Errorf("Expected requester ID user-123, got %s", queuedSong.RequesterID)
+	}
+
+	if queuedSong.RequesterName != "DJ Gopher" {
+		t.Errorf("Expected requester name DJ Gopher, got %s", queuedSong.RequesterName)
+	}
+
+	if queuedSong.EnqueuedAt.IsZero() {
+		t.Error("Expected EnqueuedAt to be set")
+	}
+}
+
+func TestPlayerPlayNext(t *testing.T) {
+	q := queue.NewQueue()
+	player := createTestPlayer(q)
+	player.IsPlaying = true
+
+	interaction := &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID: "test-interaction",
+		},
+	}
+
+	player.AddSong(interaction, &services.YoutubeResult{ID: "song1", Title: "Song 1"})
+	player.AddSong(interaction, &services.YoutubeResult{ID: "song2", Title: "Song 2"})
+	player.PlayNext(interaction, &services.YoutubeResult{ID: "next", Title: "Next Song"})
+
+	if q.Size() != 3 {
+		t.Errorf("Expected queue size to be 3, got %d", q.Size())
+	}
+
+	if q.Peek().ID != "next" {
+		t.Errorf("Expected next song at the front of the queue, got %s", q.Peek().ID)
+	}
+}
+
+func TestPlayerRestoreQueuesTracks(t *testing.T) {
+	q := queue.NewQueue()
+	player := createTestPlayer(q)
+	player.IsPlaying = true
+
+	tracks := []*queue.Track{
+		queue.NewTrack(&services.YoutubeResult{ID: "song1", Title: "Song 1"}, "user1", "User 1"),
+		queue.NewTrack(&services.YoutubeResult{ID: "song2", Title: "Song 2"}, "user2", "User 2"),
+	}
+
+	if restored, err := player.Restore(tracks, 30*time.Second); restored != 2 || err != nil {
+		t.Fatalf("Expected 2 songs restored, got %d, %v", restored, err)
+	}
+
+	songs := q.GetSongs()
+	if len(songs) != 2 {
+		t.Fatalf("Expected 2 restored songs, got %d", len(songs))
+	}
+
+	// Restored tracks keep their original requester
+	if songs[0] != tracks[0] || songs[1].RequesterName != "User 2" {
+		t.Error("Expected restored tracks to be queued as saved")
+	}
+}
+
+func TestPlayerRestoreAppliesLimits(t *testing.T) {
+	q := queue.NewQueue()
+	p := createTestPlayer(q)
+	p.IsPlaying = true
+	p.SetLimits(player.Limits{MaxTrackLength: time.Hour, MaxQueueSize: 2})
+
+	tracks := []*queue.Track{
+		queue.NewTrack(&services.YoutubeResult{ID: "rain", Title: "10 Hours of Rain", Length: 10 * time.Hour}, "user1", "User 1"),
+		queue.NewTrack(&services.YoutubeResult{ID: "song1", Title: "Song 1", Length: time.Minute}, "user1", "User 1"),
+		queue.NewTrack(&services.YoutubeResult{ID: "song2", Title: "Song 2", Length: time.Minute}, "user2", "User 2"),
+		queue.NewTrack(&services.YoutubeResult{ID: "song3", Title: "Song 3", Length: time.Minute}, "user2", "User 2"),
+	}
+
+	restored, err := p.Restore(tracks, 30*time.Second)
+	if restored != 2 || err == nil {
+		t.Fatalf("Expected 2 songs restored and the rest skipped, got %d, %v", restored, err)
+	}
+	if songs := q.GetSongs(); songs[0].ID != "song1" || songs[1].ID != "song2" {
+		t.Errorf("Expected the songs within the limits to be queued, got %s and %s", songs[0].ID, songs[1].ID)
+	}
+}
+
+// memberInteraction is an interaction from a guild member with the given ID
+func memberInteraction(userID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:     "test-interaction",
+			Member: &discordgo.Member{User: &discordgo.User{ID: userID, Username: userID}},
+		},
+	}
+}
+
+func TestPlayerRejectsTracksOverMaxLength(t *testing.T) {
+	q := queue.NewQueue()
+	p := createTestPlayer(q)
+	p.IsPlaying = true
+	p.SetLimits(player.Limits{MaxTrackLength: time.Hour})
+
+	rain := &services.YoutubeResult{ID: "rain", Title: "10 Hours of Rain", Length: 10 * time.Hour}
+	if err := p.AddSong(memberInteraction("user1"), rain); err == nil {
+		t.Error("Expected a song over the length limit to be rejected")
+	}
+	if err := p.CheckLimits(memberInteraction("user1"), rain); err == nil {
+		t.Error("Expected CheckLimits to reject a song over the length limit")
+	}
+
+	// Live streams have no known length and aren't held to the limit
+	live := &services.YoutubeResult{ID: "live", Title: "Live Radio"}
+	if err := p.AddSong(memberInteraction("user1"), live); err != nil {
+		t.Errorf("Expected a song of unknown length to be allowed, got %v", err)
+	}
+	if q.Size() != 1 {
+		t.Errorf("Expected only the live stream to be queued, got %d songs", q.Size())
+	}
+}
+
+func TestPlayerEnforcesQueueAndUserLimits(t *testing.T) {
+	q := queue.NewQueue()
+	p := createTestPlayer(q)
+	p.IsPlaying = true
+	p.SetLimits(player.Limits{MaxQueueSize: 3, MaxTracksPerUser: 2})
+
+	for n := 1; n <= 2; n++ {
+		if err := p.AddSong(memberInteraction("user1"), &services.YoutubeResult{ID: fmt.Sprintf("user1-%d", n)}); err != nil {
+			t.Fatalf("Unexpected error adding song %d: %v", n, err)
+		}
+	}
+	if err := p.PlayNext(memberInteraction("user1"), &services.YoutubeResult{ID: "user1-3"}); err == nil {
+		t.Error("Expected a third song from the same user to be rejected")
+	}
+
+	if err := p.AddSong(memberInteraction("user2"), &services.YoutubeResult{ID: "user2-1"}); err != nil {
+		t.Fatalf("Unexpected error adding another user's song: %v", err)
+	}
+	if err := p.AddSong(memberInteraction("user3"), &services.YoutubeResult{ID: "user3-1"}); err == nil {
+		t.Error("Expected a song to be rejected once the queue is full")
+	}
+	if q.Size() != 3 {
+		t.Errorf("Expected 3 songs in the queue, got %d", q.Size())
+	}
+}
+
+func TestPlayerAddSongsSkipsSongsOverLimits(t *testing.T) {
+	q := queue.NewQueue()
+	p := createTestPlayer(q)
+	p.IsPlaying = true
+	p.SetLimits(player.Limits{MaxQueueSize: 3, MaxTrackLength: time.Hour})
+
+	playlist := []services.YoutubeResult{
+		{ID: "song1", Length: 3 * time.Minute},
+		{ID: "long", Length: 2 * time.Hour},
+		{ID: "song2", Length: 3 * time.Minute},
+		{ID: "song3", Length: 3 * time.Minute},
+		{ID: "song4", Length: 3 * time.Minute},
+	}
+
+	added, err := p.AddSongs(memberInteraction("user1"), playlist)
+	if added != 3 {
+		t.Errorf("Expected 3 songs to be added, got %d", added)
+	}
+	if err == nil {
+		t.Error("Expected an error describing the skipped songs")
+	}
+	if ids := []string{"song1", "song2", "song3"}; q.Size() != len(ids) || q.Peek().ID != ids[0] {
+		t.Errorf("Expected %v to be queued, got %d songs", ids, q.Size())
+	}
+}
+
+// createLoopTestPlayer creates a Player that reports the songs its playback loop
+// announces on started, and signals idle once the loop runs out of songs.
+// With no voice connection every song fails to stream, so the loop moves straight on.
+func createLoopTestPlayer(q queue.QueueInterface) (p *player.Player, started chan string, idle chan struct{}) {
+	p = createTestPlayer(q)
+	started = make(chan string, 10)
+	idle = make(chan struct{}, 10)
+	p.OnSendNowPlaying = func(song *queue.Track, content string) error {
+		started <- song.ID
+		return nil
+	}
+	p.OnIdle = func() {
+		idle <- struct{}{}
+	}
+	return p, started, idle
+}
+
+// waitFor fails the test if nothing arrives on ch within a second
+func waitFor[T any](t *testing.T, ch chan T, what string) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(time.Second):
+		t.Fatalf("Expected %s", what)
+	}
+	var zero T
+	return zero
+}
+
+func TestPlayerStartsAfterStopWhileIdle(t *testing.T) {
+	p, started, idle := createLoopTestPlayer(queue.NewQueue())
+
+	// Stopped while idle, as the idle timer does when it leaves the channel
+	p.Stop()
+
+	if err := p.AddSong(memberInteraction("user1"), &services.YoutubeResult{ID: "song1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id := waitFor(t, started, "the playback loop to start song1"); id != "song1" {
+		t.Errorf("Expected song1 to start, got %s", id)
+	}
+	waitFor(t, idle, "the playback loop to go idle")
+}
+
+func TestPlayerStalePauseDoesNotCarryOver(t *testing.T) {
+	p, started, idle := createLoopTestPlayer(queue.NewQueue())
+
+	// Paused between songs, when nothing is streaming to pick it up
+	p.IsPlaying = true
+	if !p.Pause() {
+		t.Fatal("Expected the pause to be accepted")
+	}
+	p.IsPlaying = false
+
+	pausedAt := make(chan bool, 10)
+	p.OnSendNowPlaying = func(song *queue.Track, content string) error {
+		pausedAt <- p.IsPlayerPaused()
+		started <- song.ID
+		return nil
+	}
+
+	songs := []services.YoutubeResult{{ID: "song1"}, {ID: "song2"}}
+	if _, err := p.AddSongs(memberInteraction("user1"), songs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitFor(t, pausedAt, "song1 to start")
+	if waitFor(t, pausedAt, "song2 to start") {
+		t.Error("Expected the next song not to start paused")
+	}
+	waitFor(t, idle, "the playback loop to go idle")
+}
@@ -0,0 +1,46 @@
+package player
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// DefaultVolume is the volume a new player starts at, as a percentage
+	DefaultVolume = 100
+	// MaxVolume is the loudest the volume can be set to, as a percentage
+	MaxVolume = 200
+)
+
+// SetVolume sets the playback volume as a percentage between 0 and MaxVolume.
+// It takes effect on the next frame, so there's no need to restart the stream.
+func (p *Player) SetVolume(percent int) error {
+	if percent < 0 || percent > MaxVolume {
+		return fmt.Errorf("volume must be between 0 and %d", MaxVolume)
+	}
+	p.volume.Store(int32(percent))
+	return nil
+}
+
+// GetVolume returns the playback volume as a percentage.
+func (p *Player) GetVolume() int {
+	return int(p.volume.Load())
+}
+
+// applyVolume scales the PCM samples in place, clipping anything that
+// would overflow an int16.
+func applyVolume(pcm []int16, percent int) {
+	if percent == DefaultVolume {
+		return
+	}
+
+	for i, sample := range pcm {
+		scaled := int32(sample) * int32(percent) / DefaultVolume
+		if scaled > math.MaxInt16 {
+			scaled = math.MaxInt16
+		} else if scaled < math.MinInt16 {
+			scaled = math.MinInt16
+		}
+		pcm[i] = int16(scaled)
+	}
+}
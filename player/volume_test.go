@@ -0,0 +1,31 @@
+package player
+
+import (
+	"math"
+	"testing"
+)
+
+func TestApplyVolume(t *testing.T) {
+	tests := []struct {
+		name     string
+		percent  int
+		input    []int16
+		expected []int16
+	}{
+		{"unchanged at default", DefaultVolume, []int16{1000, -1000}, []int16{1000, -1000}},
+		{"half volume", 50, []int16{1000, -1000, 1}, []int16{500, -500, 0}},
+		{"muted", 0, []int16{1000, -1000}, []int16{0, 0}},
+		{"double volume", 200, []int16{1000, -1000}, []int16{2000, -2000}},
+		{"clips loud samples", 200, []int16{30000, -30000}, []int16{math.MaxInt16, math.MinInt16}},
+	}
+
+	for _, tt := range tests {
+		pcm := append([]int16{}, tt.input...)
+		applyVolume(pcm, tt.percent)
+		for i := range pcm {
+			if pcm[i] != tt.expected[i] {
+				t.Errorf("%s: sample %d expected %d, got %d", tt.name, i, tt.expected[i], pcm[i])
+			}
+		}
+	}
+}
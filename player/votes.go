@@ -0,0 +1,67 @@
+package player
+
+// DefaultVoteSkipPercent is the share of listeners that must vote to skip a track.
+const DefaultVoteSkipPercent = 50
+
+// SkipVote is the outcome of a vote to skip the current track.
+type SkipVote struct {
+	// Votes is how many listeners have voted to skip the track so far
+	Votes int
+	// Needed is how many votes skip the track
+	Needed int
+	// AlreadyVoted is true if the listener had already voted
+	AlreadyVoted bool
+	// Skipped is true once the vote has passed and the track is being skipped
+	Skipped bool
+}
+
+// skipVotes are the listeners who voted to skip the current track, reset when
+// a track starts. It is guarded by the player's mutex.
+type skipVotes struct {
+	voters map[string]bool
+	passed bool
+}
+
+// VotesNeeded returns how many of the listeners must vote to skip, at least one.
+func VotesNeeded(listeners, percent int) int {
+	if percent <= 0 || percent > 100 {
+		percent = DefaultVoteSkipPercent
+	}
+	needed := (listeners*percent + 99) / 100
+	return max(needed, 1)
+}
+
+// VoteSkip records a listener's vote to skip the current track and skips it
+// once there are needed votes. Votes cast after the vote has passed are ignored.
+func (p *Player) VoteSkip(userID string, needed int) SkipVote {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.votes.passed {
+		return SkipVote{Votes: len(p.votes.voters), Needed: needed, Skipped: true}
+	}
+	if p.votes.voters == nil {
+		p.votes.voters = make(map[string]bool)
+	}
+
+	vote := SkipVote{Needed: needed, AlreadyVoted: p.votes.voters[userID]}
+	p.votes.voters[userID] = true
+	vote.Votes = len(p.votes.voters)
+
+	if vote.Votes >= needed && p.IsPlaying {
+		p.votes.passed = true
+		vote.Skipped = true
+		select {
+		case p.skip <- true:
+		default:
+		}
+	}
+	return vote
+}
+
+// SkipVotes returns how many listeners have voted to skip the current track.
+func (p *Player) SkipVotes() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.votes.voters)
+}
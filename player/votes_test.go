@@ -0,0 +1,68 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coreyo-git/beatgopher/queue"
+)
+
+func TestVotesNeeded(t *testing.T) {
+	tests := []struct {
+		listeners, percent, expected int
+	}{
+		{0, 50, 1},
+		{1, 50, 1},
+		{3, 50, 2},
+		{4, 50, 2},
+		{5, 100, 5},
+		{10, 1, 1},
+		{4, 0, 2}, // unset falls back to the default
+	}
+
+	for _, tt := range tests {
+		if got := VotesNeeded(tt.listeners, tt.percent); got != tt.expected {
+			t.Errorf("VotesNeeded(%d, %d) = %d, expected %d", tt.listeners, tt.percent, got, tt.expected)
+		}
+	}
+}
+
+func TestVoteSkipPassesOnce(t *testing.T) {
+	p := NewPlayer(context.Background(), queue.NewQueue(), nil, nil, nil, nil)
+	p.IsPlaying = true
+	p.currentSong = newHistoryTrack(1)
+
+	if vote := p.VoteSkip("user1", 2); vote.Votes != 1 || vote.Skipped {
+		t.Fatalf("Expected one vote without a skip, got %+v", vote)
+	}
+	if vote := p.VoteSkip("user1", 2); !vote.AlreadyVoted || vote.Votes != 1 {
+		t.Errorf("Expected a repeat vote not to count, got %+v", vote)
+	}
+	if vote := p.VoteSkip("user2", 2); !vote.Skipped || vote.Votes != 2 {
+		t.Fatalf("Expected the second vote to skip, got %+v", vote)
+	}
+	if len(p.skip) != 1 {
+		t.Error("Expected a skip to be sent")
+	}
+
+	// Votes after the vote passed don't skip the next song too
+	<-p.skip
+	if vote := p.VoteSkip("user3", 2); !vote.Skipped {
+		t.Errorf("Expected late votes to see the vote passed, got %+v", vote)
+	}
+	if len(p.skip) != 0 {
+		t.Error("Expected no second skip")
+	}
+}
+
+func TestVotesResetWhenTrackChanges(t *testing.T) {
+	p := NewPlayer(context.Background(), queue.NewQueue(), nil, nil, nil, nil)
+	p.IsPlaying = true
+	p.setCurrentSong(newHistoryTrack(1))
+	p.VoteSkip("user1", 3)
+
+	p.setCurrentSong(newHistoryTrack(2))
+	if votes := p.SkipVotes(); votes != 0 {
+		t.Errorf("Expected votes to reset, got %d", votes)
+	}
+}
@@ -3,31 +3,47 @@ package queue
 import (
 	"sync"
 	"log"
-	"github.com/coreyo-git/beatgopher/services"
+	"math/rand"
+	"time"
 )
 
 // QueueInterface defines the contract for queue operations
 type QueueInterface interface {
-	// Enqueue adds a song to the queue
-	Enqueue(song *services.YoutubeResult)
+	// Enqueue adds a track to the queue
+	Enqueue(track *Track)
 
-	// Dequeue removes and returns the first song from the queue
-	Dequeue() *services.YoutubeResult
+	// Dequeue removes and returns the first track from the queue
+	Dequeue() *Track
 
-	// RemoveFromQueue removes a specific song from the queue
-	RemoveFromQueue(song *services.YoutubeResult) bool
+	// RemoveFromQueue removes a specific track from the queue
+	RemoveFromQueue(track *Track) bool
+
+	// Move moves the track at index from to index to
+	Move(from int, to int) bool
+
+	// Swap swaps the tracks at indexes a and b
+	Swap(a int, b int) bool
+
+	// InsertAt inserts a track at index, shifting later tracks back
+	InsertAt(index int, track *Track)
+
+	// Shuffle randomly reorders the queue, returning how many tracks moved
+	Shuffle() int
+
+	// RemoveDuplicates removes repeated songs, returning how many were removed
+	RemoveDuplicates() int
 
 	// IsEmpty returns true if the queue is empty
 	IsEmpty() bool
 
-	// Peek returns the first song without removing it
-	Peek() *services.YoutubeResult
+	// Peek returns the first track without removing it
+	Peek() *Track
 
 	// Size returns the number of songs in the queue
 	Size() int
 
-	// GetSongs returns a copy of all songs in the queue
-	GetSongs() []*services.YoutubeResult
+	// GetSongs returns a copy of all tracks in the queue
+	GetSongs() []*Track
 
 	// Clear removes all songs from the queue
 	Clear()
@@ -36,23 +52,30 @@ type QueueInterface interface {
 // FIFO queue for a single guild
 type Queue struct {
 	mu sync.Mutex 
-	songs []*services.YoutubeResult
+	songs []*Track
+	rng   *rand.Rand
 }
 
 func NewQueue() *Queue {
+	return NewQueueWithRand(rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+// NewQueueWithRand creates a queue that shuffles using rng, so tests can use a fixed seed.
+func NewQueueWithRand(rng *rand.Rand) *Queue {
 	return &Queue{
 		mu:    sync.Mutex{},
-		songs: []*services.YoutubeResult{},
+		songs: []*Track{},
+		rng:   rng,
 	}
 }
 
-func (q *Queue) Enqueue(song *services.YoutubeResult) {
+func (q *Queue) Enqueue(track *Track) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	q.songs = append(q.songs, song)
+	q.songs = append(q.songs, track)
 }
 
-func (q *Queue) Dequeue () *services.YoutubeResult {
+func (q *Queue) Dequeue () *Track {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 	if len(q.songs) == 0 {
@@ -65,7 +88,7 @@ func (q *Queue) Dequeue () *services.YoutubeResult {
 	return song
 }
 
-func (q *Queue) RemoveFromQueue(song *services.YoutubeResult) bool {
+func (q *Queue) RemoveFromQueue(track *Track) bool {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 	if len(q.songs) == 0 {
@@ -73,7 +96,7 @@ func (q *Queue) RemoveFromQueue(song *services.YoutubeResult) bool {
 		return false
 	}
 	for i := range q.songs {
-		if (q.songs)[i].ID == song.ID {
+		if (q.songs)[i] == track {
 			// Remove the element by slicing and appending
 			// This creates a new slice without the element at i
 			q.songs = append((q.songs)[:i], (q.songs)[i+1:]...)
@@ -84,13 +107,111 @@ func (q *Queue) RemoveFromQueue(song *services.YoutubeResult) bool {
 	return false
 }
 
+// Move moves the track at index from to index to, shifting the tracks in between.
+// Indexes are zero based, it returns false if either is out of range.
+func (q *Queue) Move(from int, to int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.inRange(from) || !q.inRange(to) {
+		log.Printf("Cannot move track %d to %d in a queue of %d.", from, to, len(q.songs))
+		return false
+	}
+
+	track := q.songs[from]
+	if from < to {
+		copy(q.songs[from:to], q.songs[from+1:to+1])
+	} else {
+		copy(q.songs[to+1:from+1], q.songs[to:from])
+	}
+	q.songs[to] = track
+	return true
+}
+
+// Swap swaps the tracks at indexes a and b.
+// Indexes are zero based, it returns false if either is out of range.
+func (q *Queue) Swap(a int, b int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.inRange(a) || !q.inRange(b) {
+		log.Printf("Cannot swap tracks %d and %d in a queue of %d.", a, b, len(q.songs))
+		return false
+	}
+
+	q.songs[a], q.songs[b] = q.songs[b], q.songs[a]
+	return true
+}
+
+// InsertAt inserts a track at index, shifting later tracks back.
+// Indexes past either end are clamped, so InsertAt(0, ...) always plays next.
+func (q *Queue) InsertAt(index int, track *Track) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if index < 0 {
+		index = 0
+	}
+	if index > len(q.songs) {
+		index = len(q.songs)
+	}
+
+	q.songs = append(q.songs, nil)
+	copy(q.songs[index+1:], q.songs[index:])
+	q.songs[index] = track
+}
+
+// Shuffle randomly reorders the queue, returning how many tracks ended up in a new position.
+func (q *Queue) Shuffle() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	before := make([]*Track, len(q.songs))
+	copy(before, q.songs)
+
+	q.rng.Shuffle(len(q.songs), func(i, j int) {
+		q.songs[i], q.songs[j] = q.songs[j], q.songs[i]
+	})
+
+	moved := 0
+	for i := range q.songs {
+		if q.songs[i] != before[i] {
+			moved++
+		}
+	}
+	return moved
+}
+
+// RemoveDuplicates removes any track whose song is already earlier in the queue,
+// returning how many tracks were removed.
+func (q *Queue) RemoveDuplicates() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(q.songs))
+	unique := make([]*Track, 0, len(q.songs))
+	for _, track := range q.songs {
+		if _, exists := seen[track.ID]; exists {
+			continue
+		}
+		seen[track.ID] = struct{}{}
+		unique = append(unique, track)
+	}
+
+	removed := len(q.songs) - len(unique)
+	q.songs = unique
+	return removed
+}
+
+// inRange reports whether index points at a track, callers must hold the lock
+func (q *Queue) inRange(index int) bool {
+	return index >= 0 && index < len(q.songs)
+}
+
 func (q *Queue) IsEmpty() bool {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 	return len(q.songs) == 0
 }
 
-func (q *Queue) Peek() *services.YoutubeResult {
+func (q *Queue) Peek() *Track {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
@@ -106,12 +227,12 @@ func (q *Queue) Size() int {
 	return len(q.songs)
 }
 
-func (q *Queue) GetSongs() []*services.YoutubeResult {
+func (q *Queue) GetSongs() []*Track {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	// Return a copy of the slice to avoid race conditions
-	songsCopy := make([]*services.YoutubeResult, len(q.songs))
+	songsCopy := make([]*Track, len(q.songs))
 	copy(songsCopy, q.songs)
 	return songsCopy
 }
@@ -119,5 +240,5 @@ func (q *Queue) GetSongs() []*services.YoutubeResult {
 func (q *Queue) Clear() {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	q.songs = []*services.YoutubeResult{}
+	q.songs = []*Track{}
 }
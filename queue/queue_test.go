@@ -2,8 +2,10 @@ package queue
 
 import (
 	"fmt"
+	"math/rand"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/coreyo-git/beatgopher/services"
 )
@@ -42,20 +44,24 @@ func TestQueueOperations(t *testing.T) {
 		Thumbnail: "Song3Thumbnail.link",
 	}
 
-	q.Enqueue(&song1)
+	track1 := newTestTrack(&song1)
+	track2 := newTestTrack(&song2)
+	track3 := newTestTrack(&song3)
+
+	q.Enqueue(track1)
 	if q.IsEmpty() {
 		t.Error("Expected queue not to be empty after enqueue")
 	}
 
-	q.Enqueue(&song2)
-	q.Enqueue(&song3)
+	q.Enqueue(track2)
+	q.Enqueue(track3)
 	if q.Size() != 3 {
 		t.Errorf("Expected size 3, got %d", q.Size())
 	}
 
 	// Test Peek
-	peekedItem := *q.Peek()
-	if peekedItem != song1 {
+	peekedItem := q.Peek()
+	if peekedItem != track1 {
 		t.Errorf("Expected peeked item to be Song 1, got %v", peekedItem)
 	}
 	if q.Size() != 3 { // Peek should not change size
@@ -63,24 +69,24 @@ func TestQueueOperations(t *testing.T) {
 	}
 
 	// Test Dequeue
-	dequeuedItem := *q.Dequeue()
-	if dequeuedItem != song1 {
+	dequeuedItem := q.Dequeue()
+	if dequeuedItem != track1 {
 		t.Errorf("Expected dequeued item to be Song 1, got %v", dequeuedItem)
 	}
 	if q.Size() != 2 {
 		t.Errorf("Expected size 2 after dequeue, got %d", q.Size())
 	}
 
-	dequeuedItem = *q.Dequeue()
-	if dequeuedItem != song2 {
+	dequeuedItem = q.Dequeue()
+	if dequeuedItem != track2 {
 		t.Errorf("Expected dequeued item to be Song 2, got %v", dequeuedItem)
 	}
 	if q.Size() != 1 {
 		t.Errorf("Expected size 1 after dequeue, got %d", q.Size())
 	}
 
-	dequeuedItem = *q.Dequeue()
-	if dequeuedItem != song3 {
+	dequeuedItem = q.Dequeue()
+	if dequeuedItem != track3 {
 		t.Errorf("Expected dequeued item to be Song 3, got %v", dequeuedItem)
 	}
 	if q.Size() != 0 {
@@ -128,22 +134,26 @@ func TestRemoveFromQueue(t *testing.T) {
 		Thumbnail: "https://img.youtube.com/vi/test3/default.jpg",
 	}
 
+	track1 := newTestTrack(song1)
+	track2 := newTestTrack(song2)
+	track3 := newTestTrack(song3)
+
 	// Test removing from empty queue
-	if q.RemoveFromQueue(song1) {
+	if q.RemoveFromQueue(track1) {
 		t.Error("Expected RemoveFromQueue to return false for empty queue")
 	}
 
 	// Add songs to queue
-	q.Enqueue(song1)
-	q.Enqueue(song2)
-	q.Enqueue(song3)
+	q.Enqueue(track1)
+	q.Enqueue(track2)
+	q.Enqueue(track3)
 
 	if q.Size() != 3 {
 		t.Errorf("Expected queue size 3, got %d", q.Size())
 	}
 
 	// Test removing middle song
-	if !q.RemoveFromQueue(song2) {
+	if !q.RemoveFromQueue(track2) {
 		t.Error("Expected RemoveFromQueue to return true for existing song")
 	}
 
@@ -166,7 +176,7 @@ func TestRemoveFromQueue(t *testing.T) {
 	}
 
 	// Test removing first song
-	if !q.RemoveFromQueue(song1) {
+	if !q.RemoveFromQueue(track1) {
 		t.Error("Expected RemoveFromQueue to return true for first song")
 	}
 
@@ -180,7 +190,7 @@ func TestRemoveFromQueue(t *testing.T) {
 	}
 
 	// Test removing last song
-	if !q.RemoveFromQueue(song3) {
+	if !q.RemoveFromQueue(track3) {
 		t.Error("Expected RemoveFromQueue to return true for last song")
 	}
 
@@ -198,7 +208,7 @@ func TestRemoveFromQueue(t *testing.T) {
 		Thumbnail: "https://img.youtube.com/vi/nonexistent/default.jpg",
 	}
 
-	if q.RemoveFromQueue(nonExistentSong) {
+	if q.RemoveFromQueue(newTestTrack(nonExistentSong)) {
 		t.Error("Expected RemoveFromQueue to return false for non-existent song")
 	}
 }
@@ -251,8 +261,10 @@ func TestQueueWithFilledQueue(t *testing.T) {
 	}
 
 	// Add all songs to queue
-	for _, song := range songs {
-		q.Enqueue(song)
+	tracks := make([]*Track, len(songs))
+	for i, song := range songs {
+		tracks[i] = newTestTrack(song)
+		q.Enqueue(tracks[i])
 	}
 
 	// Test initial state
@@ -266,7 +278,7 @@ func TestQueueWithFilledQueue(t *testing.T) {
 
 	// Test removing from different positions
 	// Remove middle song (index 2, "Third Song")
-	if !q.RemoveFromQueue(songs[2]) {
+	if !q.RemoveFromQueue(tracks[2]) {
 		t.Error("Failed to remove middle song")
 	}
 
@@ -285,7 +297,7 @@ func TestQueueWithFilledQueue(t *testing.T) {
 	}
 
 	// Remove first song
-	if !q.RemoveFromQueue(songs[0]) {
+	if !q.RemoveFromQueue(tracks[0]) {
 		t.Error("Failed to remove first song")
 	}
 
@@ -299,7 +311,7 @@ func TestQueueWithFilledQueue(t *testing.T) {
 	}
 
 	// Remove last song
-	if !q.RemoveFromQueue(songs[4]) {
+	if !q.RemoveFromQueue(tracks[4]) {
 		t.Error("Failed to remove last song")
 	}
 
@@ -334,7 +346,7 @@ func TestConcurrentQueueOperations(t *testing.T) {
 				URL:       fmt.Sprintf("https://youtube.com/watch?v=conc%d", i),
 				Thumbnail: fmt.Sprintf("https://img.youtube.com/vi/conc%d/default.jpg", i),
 			}
-			q.Enqueue(testSong)
+			q.Enqueue(newTestTrack(testSong))
 		}
 		wg.Done()
 	})
@@ -349,7 +361,7 @@ func TestConcurrentQueueOperations(t *testing.T) {
 				URL:       fmt.Sprintf("https://youtube.com/watch?v=conc%d", i),
 				Thumbnail: fmt.Sprintf("https://img.youtube.com/vi/conc%d/default.jpg", i),
 			}
-			q.Enqueue(testSong)
+			q.Enqueue(newTestTrack(testSong))
 		}
 		wg.Done()
 	})
@@ -385,10 +397,10 @@ func TestConcurrentQueueOperations(t *testing.T) {
 			URL:       fmt.Sprintf("https://youtube.com/watch?v=conc%d", i),
 			Thumbnail: fmt.Sprintf("https://img.youtube.com/vi/conc%d/default.jpg", i),
 		}
-		q.Enqueue(testSong)
+		q.Enqueue(newTestTrack(testSong))
 	}
 
-	songsRemoved := make([]services.YoutubeResult, numSongs)
+	songsRemoved := make([]Track, numSongs)
 	wg.Add(3)
 	// Goroutine 2: Remove songs
 	wg.Go(func() {
@@ -461,9 +473,9 @@ func TestQueueIsEmptyOnClear(t *testing.T) {
 		Thumbnail: "Song3Thumbnail.link",
 	}
 
-	q.Enqueue(&song1)
-	q.Enqueue(&song2)
-	q.Enqueue(&song3)
+	q.Enqueue(newTestTrack(&song1))
+	q.Enqueue(newTestTrack(&song2))
+	q.Enqueue(newTestTrack(&song3))
 
 	q.Clear()
 
@@ -482,6 +494,218 @@ func TestPeekOnEmptyQueue(t *testing.T) {
 	}
 }
 
+func TestMove(t *testing.T) {
+	q := newNumberedQueue(5)
+
+	// Move a track towards the back
+	if !q.Move(0, 3) {
+		t.Error("Expected Move(0, 3) to return true")
+	}
+	assertQueueOrder(t, q, []string{"1", "2", "3", "0", "4"})
+
+	// Move a track towards the front
+	if !q.Move(4, 1) {
+		t.Error("Expected Move(4, 1) to return true")
+	}
+	assertQueueOrder(t, q, []string{"1", "4", "2", "3", "0"})
+
+	// Moving a track onto itself changes nothing
+	if !q.Move(2, 2) {
+		t.Error("Expected Move(2, 2) to return true")
+	}
+	assertQueueOrder(t, q, []string{"1", "4", "2", "3", "0"})
+
+	// Out of range moves are rejected and leave the queue alone
+	if q.Move(-1, 2) || q.Move(0, 5) || q.Move(5, 0) {
+		t.Error("Expected out of range moves to return false")
+	}
+	assertQueueOrder(t, q, []string{"1", "4", "2", "3", "0"})
+
+	// Moving in an empty queue fails
+	if NewQueue().Move(0, 0) {
+		t.Error("Expected Move on an empty queue to return false")
+	}
+}
+
+func TestSwap(t *testing.T) {
+	q := newNumberedQueue(4)
+
+	if !q.Swap(0, 3) {
+		t.Error("Expected Swap(0, 3) to return true")
+	}
+	assertQueueOrder(t, q, []string{"3", "1", "2", "0"})
+
+	if !q.Swap(2, 1) {
+		t.Error("Expected Swap(2, 1) to return true")
+	}
+	assertQueueOrder(t, q, []string{"3", "2", "1", "0"})
+
+	// Out of range swaps are rejected and leave the queue alone
+	if q.Swap(0, 4) || q.Swap(-1, 0) {
+		t.Error("Expected out of range swaps to return false")
+	}
+	assertQueueOrder(t, q, []string{"3", "2", "1", "0"})
+}
+
+func TestInsertAt(t *testing.T) {
+	q := newNumberedQueue(3)
+
+	q.InsertAt(0, newTestTrack(&services.YoutubeResult{ID: "front"}))
+	assertQueueOrder(t, q, []string{"front", "0", "1", "2"})
+
+	q.InsertAt(2, newTestTrack(&services.YoutubeResult{ID: "middle"}))
+	assertQueueOrder(t, q, []string{"front", "0", "middle", "1", "2"})
+
+	q.InsertAt(5, newTestTrack(&services.YoutubeResult{ID: "back"}))
+	assertQueueOrder(t, q, []string{"front", "0", "middle", "1", "2", "back"})
+
+	// Out of range indexes are clamped to either end
+	q.InsertAt(-3, newTestTrack(&services.YoutubeResult{ID: "clamped-front"}))
+	q.InsertAt(100, newTestTrack(&services.YoutubeResult{ID: "clamped-back"}))
+	assertQueueOrder(t, q, []string{"clamped-front", "front", "0", "middle", "1", "2", "back", "clamped-back"})
+
+	// Inserting into an empty queue
+	empty := NewQueue()
+	empty.InsertAt(0, newTestTrack(&services.YoutubeResult{ID: "only"}))
+	assertQueueOrder(t, empty, []string{"only"})
+}
+
+func TestConcurrentReorderOperations(t *testing.T) {
+	numSongs := 20
+	q := newNumberedQueue(numSongs)
+	var wg sync.WaitGroup
+
+	// Reorder from several goroutines at once while inserting new tracks
+	wg.Go(func() {
+		for i := 0; i < 100; i++ {
+			q.Move(i%numSongs, (i*7)%numSongs)
+		}
+	})
+	wg.Go(func() {
+		for i := 0; i < 100; i++ {
+			q.Swap(i%numSongs, (i*3)%numSongs)
+		}
+	})
+	wg.Go(func() {
+		for i := 0; i < 10; i++ {
+			q.InsertAt(0, newTestTrack(&services.YoutubeResult{ID: fmt.Sprintf("inserted%d", i)}))
+		}
+	})
+
+	wg.Wait()
+
+	// Nothing should be lost or duplicated
+	if q.Size() != numSongs+10 {
+		t.Errorf("Expected %d songs, got %d", numSongs+10, q.Size())
+	}
+	if hasDuplicates(queueIDs(q)) {
+		t.Errorf("Found duplicate IDs after concurrent reordering: %v", queueIDs(q))
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	numSongs := 10
+	q := NewQueueWithRand(rand.New(rand.NewSource(42)))
+	for i := 0; i < numSongs; i++ {
+		q.Enqueue(newTestTrack(&services.YoutubeResult{ID: fmt.Sprintf("%d", i)}))
+	}
+	before := queueIDs(q)
+
+	moved := q.Shuffle()
+	after := queueIDs(q)
+
+	// The same seed always gives the same order
+	other := NewQueueWithRand(rand.New(rand.NewSource(42)))
+	for i := 0; i < numSongs; i++ {
+		other.Enqueue(newTestTrack(&services.YoutubeResult{ID: fmt.Sprintf("%d", i)}))
+	}
+	other.Shuffle()
+	assertQueueOrder(t, other, after)
+
+	// The moved count matches the positions that changed
+	changed := 0
+	for i := range before {
+		if before[i] != after[i] {
+			changed++
+		}
+	}
+	if moved != changed {
+		t.Errorf("Expected Shuffle() to report %d moved tracks, got %d", changed, moved)
+	}
+	if moved == 0 {
+		t.Error("Expected a seeded shuffle of 10 tracks to move something")
+	}
+
+	// Nothing is lost or duplicated
+	if q.Size() != numSongs || hasDuplicates(after) {
+		t.Errorf("Expected %d unique tracks after shuffle, got %v", numSongs, after)
+	}
+
+	// Shuffling an empty queue is a no-op
+	if NewQueue().Shuffle() != 0 {
+		t.Error("Expected shuffling an empty queue to move nothing")
+	}
+}
+
+func TestRemoveDuplicates(t *testing.T) {
+	q := NewQueue()
+	for _, id := range []string{"a", "b", "a", "c", "b", "a"} {
+		q.Enqueue(newTestTrack(&services.YoutubeResult{ID: id}))
+	}
+
+	removed := q.RemoveDuplicates()
+	if removed != 3 {
+		t.Errorf("Expected 3 duplicates removed, got %d", removed)
+	}
+
+	// The first copy of each song keeps its place
+	assertQueueOrder(t, q, []string{"a", "b", "c"})
+
+	if q.RemoveDuplicates() != 0 {
+		t.Error("Expected no duplicates removed from a unique queue")
+	}
+}
+
+// newNumberedQueue creates a queue of n tracks with IDs "0" to "n-1"
+func newNumberedQueue(n int) *Queue {
+	q := NewQueue()
+	for i := 0; i < n; i++ {
+		q.Enqueue(newTestTrack(&services.YoutubeResult{
+			ID:    fmt.Sprintf("%d", i),
+			Title: fmt.Sprintf("Song %d", i),
+		}))
+	}
+	return q
+}
+
+// queueIDs returns the IDs of the tracks in the queue, in order
+func queueIDs(q *Queue) []string {
+	ids := []string{}
+	for _, track := range q.GetSongs() {
+		ids = append(ids, track.ID)
+	}
+	return ids
+}
+
+// assertQueueOrder checks the queue holds the expected IDs in order
+func assertQueueOrder(t *testing.T, q *Queue, expected []string) {
+	t.Helper()
+	actual := queueIDs(q)
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected queue %v, got %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Expected queue %v, got %v", expected, actual)
+		}
+	}
+}
+
+// newTestTrack wraps a song in a track requested by a test user
+func newTestTrack(song *services.YoutubeResult) *Track {
+	return NewTrack(song, "test-user", "Test User")
+}
+
 // hasDuplicates checks if a slice of comparable elements has any duplicates.
 func hasDuplicates[T comparable](slice []T) bool {
 	// A map is used as a set, with the element type as the key and
@@ -500,3 +724,47 @@ func hasDuplicates[T comparable](slice []T) bool {
 	// No duplicates were found after checking all elements.
 	return false
 }
+
+func TestTotalDuration(t *testing.T) {
+	tracks := []*Track{
+		newTestTrack(&services.YoutubeResult{ID: "1", Duration: "3:30", Length: 3*time.Minute + 30*time.Second}),
+		newTestTrack(&services.YoutubeResult{ID: "2", Duration: "1:00:00", Length: time.Hour}),
+		newTestTrack(&services.YoutubeResult{ID: "3", Duration: "NA"}),
+	}
+
+	total, unknown := TotalDuration(tracks)
+	if total != time.Hour+3*time.Minute+30*time.Second {
+		t.Errorf("Expected total of 1:03:30, got %v", total)
+	}
+	if unknown != 1 {
+		t.Errorf("Expected 1 track with an unknown length, got %d", unknown)
+	}
+
+	if total, unknown := TotalDuration(nil); total != 0 || unknown != 0 {
+		t.Errorf("Expected nothing for no tracks, got %v and %d", total, unknown)
+	}
+}
+
+func TestWaitTimes(t *testing.T) {
+	tracks := []*Track{
+		newTestTrack(&services.YoutubeResult{ID: "1", Length: 3 * time.Minute}),
+		newTestTrack(&services.YoutubeResult{ID: "2", Length: 2 * time.Minute}),
+		newTestTrack(&services.YoutubeResult{ID: "live"}),
+		newTestTrack(&services.YoutubeResult{ID: "4", Length: time.Minute}),
+	}
+
+	waits := WaitTimes(tracks, 30*time.Second)
+	expected := []time.Duration{30 * time.Second, 3*time.Minute + 30*time.Second, 5*time.Minute + 30*time.Second}
+	if len(waits) != len(expected) {
+		t.Fatalf("Expected waits up to the live stream, got %v", waits)
+	}
+	for i := range expected {
+		if waits[i] != expected[i] {
+			t.Errorf("Track %d: expected wait %v, got %v", i+1, expected[i], waits[i])
+		}
+	}
+
+	if waits := WaitTimes(nil, time.Minute); len(waits) != 0 {
+		t.Errorf("Expected no waits for an empty queue, got %v", waits)
+	}
+}
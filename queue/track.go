@@ -0,0 +1,55 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/coreyo-git/beatgopher/services"
+)
+
+// Track is a song in the queue along with who asked for it.
+// The song is embedded so its fields can be read straight off the track.
+type Track struct {
+	*services.YoutubeResult
+	RequesterID   string    `json:"requester_id"`
+	RequesterName string    `json:"requester_name"`
+	EnqueuedAt    time.Time `json:"enqueued_at"`
+}
+
+// NewTrack wraps a song with the user who requested it, stamped with the current time.
+func NewTrack(song *services.YoutubeResult, requesterID string, requesterName string) *Track {
+	return &Track{
+		YoutubeResult: song,
+		RequesterID:   requesterID,
+		RequesterName: requesterName,
+		EnqueuedAt:    time.Now(),
+	}
+}
+
+// TotalDuration adds up the length of the tracks. Tracks without a known
+// length, such as live streams, are left out and counted in unknown.
+func TotalDuration(tracks []*Track) (total time.Duration, unknown int) {
+	for _, track := range tracks {
+		if track.Length <= 0 {
+			unknown++
+			continue
+		}
+		total += track.Length
+	}
+	return total, unknown
+}
+
+// WaitTimes returns how long until each track starts playing, given how much
+// is left of the song playing now. Waits can only be worked out up to the first
+// track with an unknown length, so fewer waits than tracks may be returned.
+func WaitTimes(tracks []*Track, remaining time.Duration) []time.Duration {
+	waits := make([]time.Duration, 0, len(tracks))
+	wait := remaining
+	for _, track := range tracks {
+		waits = append(waits, wait)
+		if track.Length <= 0 {
+			break
+		}
+		wait += track.Length
+	}
+	return waits
+}
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"os/exec"
+	"strconv"
+	"time"
 )
 
 type AudioStream struct {
@@ -12,11 +15,32 @@ type AudioStream struct {
 	Ytdlp        *exec.Cmd
 	Ffmpeg       *exec.Cmd
 	Stdout       io.ReadCloser
+	// cancel kills yt-dlp and ffmpeg if they are still running
+	cancel context.CancelFunc
+	// cleanup removes yt-dlp's working directory for this stream
+	cleanup func()
 }
 
-func NewAudioStream(url string) (*AudioStream, error) {
-	ffmpegStdout, ytdlp, ffmpeg, err := setupAudioStream(url)
+// ffmpegPath is the ffmpeg executable, tests point it at a stand-in
+var ffmpegPath = "ffmpeg"
+
+// NewAudioStream starts streaming the audio from url, starting offset into the song.
+// Both processes are killed when ctx is cancelled or the stream is closed.
+func NewAudioStream(ctx context.Context, url string, offset time.Duration) (*AudioStream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	ytdlp, cleanup, err := ytdlpCommand(ctx, "stream", url,
+		"-f", "bestaudio",
+		"-o", "-", // output to stdout
+	)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	ffmpegStdout, ffmpeg, err := setupAudioStream(ctx, ytdlp, offset)
 	if err != nil {
+		cancel()
+		cleanup()
 		return nil, err
 	}
 
@@ -25,61 +49,67 @@ func NewAudioStream(url string) (*AudioStream, error) {
 		Ytdlp:        ytdlp,
 		Ffmpeg:       ffmpeg,
 		Stdout:       nil,
+		cancel:       cancel,
+		cleanup:      cleanup,
 	}, nil
 }
 
 func (as *AudioStream) Close() {
 	log.Printf("Closing audio stream")
+	if as.cancel != nil {
+		as.cancel()
+	}
 	if as.Ytdlp != nil && as.Ytdlp.Process != nil {
 		as.Ytdlp.Process.Kill()
 	}
 	if as.Ffmpeg != nil && as.Ffmpeg.Process != nil {
 		as.Ffmpeg.Process.Kill()
 	}
+	if as.cleanup != nil {
+		as.cleanup()
+	}
 }
 
-// GetAudioStream returns a reader with the raw audio data from a YouTube URL.
-func setupAudioStream(url string) (io.ReadCloser, *exec.Cmd, *exec.Cmd, error) {
-	ytdlpArgs := []string{
-		url,
-		"-f", "bestaudio",
-		"-o", "-", // output to stdout
+// setupAudioStream pipes yt-dlp into ffmpeg and returns a reader with the raw audio data.
+func setupAudioStream(ctx context.Context, ytdlp *exec.Cmd, offset time.Duration) (io.ReadCloser, *exec.Cmd, error) {
+	ffmpegArgs := []string{}
+	if offset > 0 {
+		// -ss before the input discards everything up to the offset
+		ffmpegArgs = append(ffmpegArgs, "-ss", strconv.FormatFloat(offset.Seconds(), 'f', 3, 64))
 	}
-	ytdlp := exec.Command("yt-dlp", ytdlpArgs...)
-
-	ffmpegArgs := []string{
+	ffmpegArgs = append(ffmpegArgs,
 		"-i", "pipe:0", // input from stdin
 		"-f", "s16le",
 		"-ar", "48000",
 		"-ac", "2",
 		"pipe:1", // output to stdout
-	}
-	ffmpeg := exec.Command("ffmpeg", ffmpegArgs...)
+	)
+	ffmpeg := exec.CommandContext(ctx, ffmpegPath, ffmpegArgs...)
 
 	// Pipe yt-dlp's stdout to ffmpeg's stdin
 	ytdlpStdout, err := ytdlp.StdoutPipe()
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("error creating yt-dlp stdout pipe: %w", err)
+		return nil, nil, fmt.Errorf("error creating yt-dlp stdout pipe: %w", err)
 	}
 	ffmpeg.Stdin = ytdlpStdout
 
 	// Get ffmpeg's stdout pipe
 	ffmpegStdout, err := ffmpeg.StdoutPipe()
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("error creating ffmpeg stdout pipe: %w", err)
+		return nil, nil, fmt.Errorf("error creating ffmpeg stdout pipe: %w", err)
 	}
 
 	// Start both processes
 	if err := ytdlp.Start(); err != nil {
-		return nil, nil, nil, fmt.Errorf("error starting yt-dlp: %w", err)
+		return nil, nil, fmt.Errorf("error starting yt-dlp: %w", err)
 	}
 
 	if err := ffmpeg.Start(); err != nil {
 		// Clean up yt-dlp since it's already running
 		ytdlpStdout.Close()
 		ytdlp.Process.Kill()
-		return nil, nil, nil, fmt.Errorf("error starting ffmpeg: %w", err)
+		return nil, nil, fmt.Errorf("error starting ffmpeg: %w", err)
 	}
 
-	return ffmpegStdout, ytdlp, ffmpeg, nil
+	return ffmpegStdout, ffmpeg, nil
 }
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// killWithin is how long a cancelled process has to exit before a test fails
+const killWithin = 5 * time.Second
+
+// fakeCommand points *path at a script that hangs, standing in for yt-dlp or ffmpeg
+func fakeCommand(t *testing.T, path *string) {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "hang")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexec sleep 30\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	original := *path
+	*path = script
+	t.Cleanup(func() { *path = original })
+}
+
+func TestGetYoutubeInfoKilledOnTimeout(t *testing.T) {
+	if err := SetWorkDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	fakeCommand(t, &ytdlpPath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := GetYoutubeInfo(ctx, "https://example.com"); err == nil {
+		t.Fatal("Expected an error once the timeout passed")
+	}
+	if elapsed := time.Since(start); elapsed > killWithin {
+		t.Errorf("Expected yt-dlp to be killed at the timeout, took %v", elapsed)
+	}
+}
+
+func TestSearchYoutubeStartsNothingWhenCancelled(t *testing.T) {
+	if err := SetWorkDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	fakeCommand(t, &ytdlpPath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := SearchYoutube(ctx, "lofi"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// waitExit waits for both of a stream's processes to exit
+func waitExit(t *testing.T, stream *AudioStream) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		stream.Ffmpeg.Wait()
+		stream.Ytdlp.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(killWithin):
+		t.Fatal("Expected yt-dlp and ffmpeg to be killed")
+	}
+}
+
+func TestNewAudioStreamKilledWhenContextCancelled(t *testing.T) {
+	if err := SetWorkDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	fakeCommand(t, &ytdlpPath)
+	fakeCommand(t, &ffmpegPath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := NewAudioStream(ctx, "https://example.com", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	cancel()
+	waitExit(t, stream)
+}
+
+func TestAudioStreamCloseKillsProcesses(t *testing.T) {
+	if err := SetWorkDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	fakeCommand(t, &ytdlpPath)
+	fakeCommand(t, &ffmpegPath)
+
+	stream, err := NewAudioStream(context.Background(), "https://example.com", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stream.Close()
+	waitExit(t, stream)
+	if _, err := os.Stat(stream.Ytdlp.Dir); !os.IsNotExist(err) {
+		t.Error("Expected the work directory to be removed")
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses a clock style duration such as "83", "1:23" or "1:02:03",
+// the same format yt-dlp uses for duration_string.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "NA" {
+		return 0, fmt.Errorf("no duration available")
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+
+	var total time.Duration
+	for _, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		total = total*60 + time.Duration(value)*time.Second
+	}
+
+	return total, nil
+}
+
+// FormatDuration formats a duration as "m:ss", or "h:mm:ss" when it is an hour or longer.
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Truncate(time.Second)
+
+	hours := int(d / time.Hour)
+	minutes := int(d%time.Hour) / int(time.Minute)
+	seconds := int(d%time.Minute) / int(time.Second)
+
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"45", 45 * time.Second},
+		{"3:30", 3*time.Minute + 30*time.Second},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"0:00", 0},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseDuration(%q) = %v, expected %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	for _, input := range []string{"", "NA", "abc", "1:-2", "1:2:3:4"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("Expected ParseDuration(%q) to return an error", input)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{0, "0:00"},
+		{5 * time.Second, "0:05"},
+		{3*time.Minute + 30*time.Second + 500*time.Millisecond, "3:30"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1:02:03"},
+		{-time.Second, "0:00"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDuration(tt.input); got != tt.expected {
+			t.Errorf("FormatDuration(%v) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -1,21 +1,29 @@
 package services
 
+import (
+	"context"
+	"time"
+)
+
 // YoutubeServiceInterface defines the contract for YouTube operations
 type YoutubeServiceInterface interface {
 	// GetYoutubeInfo gets information about a YouTube video from its URL
-	GetYoutubeInfo(url string) (YoutubeResult, error)
+	GetYoutubeInfo(ctx context.Context, url string) (YoutubeResult, error)
 
 	// SearchYoutube searches for a YouTube video by query
-	SearchYoutube(query string) (YoutubeResult, error)
+	SearchYoutube(ctx context.Context, query string) (YoutubeResult, error)
+
+	// SearchYoutubeResults searches YouTube and returns up to limit results
+	SearchYoutubeResults(ctx context.Context, query string, limit int) ([]YoutubeResult, error)
 
 	// GetYoutubePlaylistInfo gets information about a YouTube playlist
-	GetYoutubePlaylistInfo(playlistURL string, total int64, randomizeSongs bool) ([]YoutubeResult, error)
+	GetYoutubePlaylistInfo(ctx context.Context, playlistURL string, total int64, randomizeSongs bool) ([]YoutubeResult, error)
 }
 
 // AudioStreamInterface defines the contract for audio streaming operations
 type AudioStreamInterface interface {
-	// NewAudioStream creates a new audio stream from a URL
-	NewAudioStream(url string) (*AudioStream, error)
+	// NewAudioStream creates a new audio stream from a URL, starting at offset
+	NewAudioStream(ctx context.Context, url string, offset time.Duration) (*AudioStream, error)
 	// Close closes the audio stream
 	Close()
 }
@@ -23,9 +31,9 @@ type AudioStreamInterface interface {
 // AudioStreamProvider is a concrete implementation of AudioStreamInterface
 type AudioStreamProvider struct{}
 
-// NewAudioStream creates a new audio stream from a URL
-func (asp *AudioStreamProvider) NewAudioStream(url string) (*AudioStream, error) {
-	return NewAudioStream(url)
+// NewAudioStream creates a new audio stream from a URL, starting at offset
+func (asp *AudioStreamProvider) NewAudioStream(ctx context.Context, url string, offset time.Duration) (*AudioStream, error) {
+	return NewAudioStream(ctx, url, offset)
 }
 
 // Close closes the audio stream
@@ -38,18 +46,23 @@ func (asp *AudioStreamProvider) Close() {
 type YoutubeService struct{}
 
 // GetYoutubeInfo gets information about a YouTube video from its URL
-func (ys *YoutubeService) GetYoutubeInfo(url string) (YoutubeResult, error) {
-	return GetYoutubeInfo(url)
+func (ys *YoutubeService) GetYoutubeInfo(ctx context.Context, url string) (YoutubeResult, error) {
+	return GetYoutubeInfo(ctx, url)
 }
 
 // SearchYoutube searches for a YouTube video by query
-func (ys *YoutubeService) SearchYoutube(query string) (YoutubeResult, error) {
-	return SearchYoutube(query)
+func (ys *YoutubeService) SearchYoutube(ctx context.Context, query string) (YoutubeResult, error) {
+	return SearchYoutube(ctx, query)
+}
+
+// SearchYoutubeResults searches YouTube and returns up to limit results
+func (ys *YoutubeService) SearchYoutubeResults(ctx context.Context, query string, limit int) ([]YoutubeResult, error) {
+	return SearchYoutubeResults(ctx, query, limit)
 }
 
 // GetYoutubePlaylistInfo gets information about a YouTube playlist
-func (ys *YoutubeService) GetYoutubePlaylistInfo(playlistURL string, total int64, randomizeSongs bool) ([]YoutubeResult, error) {
-	return GetYoutubePlaylistInfo(playlistURL, total, randomizeSongs)
+func (ys *YoutubeService) GetYoutubePlaylistInfo(ctx context.Context, playlistURL string, total int64, randomizeSongs bool) ([]YoutubeResult, error) {
+	return GetYoutubePlaylistInfo(ctx, playlistURL, total, randomizeSongs)
 }
 
 // Verify that YoutubeService implements YoutubeServiceInterface at compile time
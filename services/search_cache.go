@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSearchTimeout is returned when a search doesn't finish in the time given.
+// The search keeps running and its results are cached for the next lookup.
+var ErrSearchTimeout = errors.New("search timed out")
+
+// searchRunTimeout bounds a shared search, it outlives whoever started it so
+// the results can be cached but shouldn't run forever.
+const searchRunTimeout = 30 * time.Second
+
+// SearchCache remembers recent search results so repeated queries, such as those
+// sent while a user is typing, don't each run yt-dlp.
+type SearchCache struct {
+	// ctx is what shared searches run under, so they still end on shutdown
+	ctx        context.Context
+	search     func(ctx context.Context, query string) ([]YoutubeResult, error)
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]searchCacheEntry
+	inflight map[string]*searchCall
+}
+
+type searchCacheEntry struct {
+	results   []YoutubeResult
+	fetchedAt time.Time
+}
+
+// searchCall is a search that is still running, shared by everyone waiting on the same query
+type searchCall struct {
+	done    chan struct{}
+	results []YoutubeResult
+	err     error
+}
+
+// NewSearchCache creates a cache in front of search that keeps up to maxEntries
+// queries for ttl. Searches run under ctx, cancel it on shutdown.
+func NewSearchCache(ctx context.Context, search func(ctx context.Context, query string) ([]YoutubeResult, error), ttl time.Duration, maxEntries int) *SearchCache {
+	return &SearchCache{
+		ctx:        ctx,
+		search:     search,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]searchCacheEntry),
+		inflight:   make(map[string]*searchCall),
+	}
+}
+
+// Cached returns the results for query if they are still fresh.
+func (c *SearchCache) Cached(query string) ([]YoutubeResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lookup(normalizeQuery(query))
+}
+
+// Search returns the cached results for query, or runs the search and waits up to
+// timeout for it. Concurrent searches for the same query share a single call.
+// Cancelling ctx stops the wait but not the shared search, which runs under the cache's context.
+func (c *SearchCache) Search(ctx context.Context, query string, timeout time.Duration) ([]YoutubeResult, error) {
+	key := normalizeQuery(query)
+
+	c.mu.Lock()
+	if results, ok := c.lookup(key); ok {
+		c.mu.Unlock()
+		return results, nil
+	}
+	call, ok := c.inflight[key]
+	if !ok {
+		call = &searchCall{done: make(chan struct{})}
+		c.inflight[key] = call
+		go c.run(key, query, call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.results, call.err
+	case <-time.After(timeout):
+		return nil, ErrSearchTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// run performs the search and caches the results if it succeeds
+func (c *SearchCache) run(key string, query string, call *searchCall) {
+	ctx, cancel := context.WithTimeout(c.ctx, searchRunTimeout)
+	call.results, call.err = c.search(ctx, query)
+	cancel()
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if call.err == nil {
+		c.store(key, call.results)
+	}
+	c.mu.Unlock()
+
+	close(call.done)
+}
+
+// lookup returns fresh results for key, dropping them if they've expired. c.mu must be held.
+func (c *SearchCache) lookup(key string) ([]YoutubeResult, bool) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.now().Sub(entry.fetchedAt) > c.ttl {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.results, true
+}
+
+// store caches results for key, evicting the oldest entry when full. c.mu must be held.
+func (c *SearchCache) store(key string, results []YoutubeResult) {
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, entry := range c.entries {
+			if oldestKey == "" || entry.fetchedAt.Before(oldest) {
+				oldestKey, oldest = k, entry.fetchedAt
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+	c.entries[key] = searchCacheEntry{results: results, fetchedAt: c.now()}
+}
+
+// normalizeQuery makes queries that only differ by case or spacing share a cache entry
+func normalizeQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
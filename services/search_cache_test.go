@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingSearch returns a search func that records how often it's called
+func countingSearch(calls *atomic.Int32, delay time.Duration) func(context.Context, string) ([]YoutubeResult, error) {
+	return func(ctx context.Context, query string) ([]YoutubeResult, error) {
+		calls.Add(1)
+		time.Sleep(delay)
+		return []YoutubeResult{{ID: query, Title: query}}, nil
+	}
+}
+
+func TestSearchCacheReusesResults(t *testing.T) {
+	var calls atomic.Int32
+	cache := NewSearchCache(context.Background(), countingSearch(&calls, 0), time.Minute, 10)
+
+	if _, ok := cache.Cached("lofi"); ok {
+		t.Error("Expected nothing cached before the first search")
+	}
+
+	for _, query := range []string{"lofi", "LoFi", "  lofi "} {
+		results, err := cache.Search(context.Background(), query, time.Second)
+		if err != nil || len(results) != 1 {
+			t.Fatalf("Search(%q) = %v, %v", query, results, err)
+		}
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 search, got %d", calls.Load())
+	}
+	if _, ok := cache.Cached("lofi"); !ok {
+		t.Error("Expected results to be cached")
+	}
+}
+
+func TestSearchCacheExpires(t *testing.T) {
+	var calls atomic.Int32
+	cache := NewSearchCache(context.Background(), countingSearch(&calls, 0), time.Minute, 10)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.Search(context.Background(), "lofi", time.Second)
+	now = now.Add(2 * time.Minute)
+
+	if _, ok := cache.Cached("lofi"); ok {
+		t.Error("Expected cached results to expire")
+	}
+	cache.Search(context.Background(), "lofi", time.Second)
+	if calls.Load() != 2 {
+		t.Errorf("Expected expired query to be searched again, got %d searches", calls.Load())
+	}
+}
+
+func TestSearchCacheEvictsOldest(t *testing.T) {
+	var calls atomic.Int32
+	cache := NewSearchCache(context.Background(), countingSearch(&calls, 0), time.Minute, 2)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for _, query := range []string{"one", "two", "three"} {
+		cache.Search(context.Background(), query, time.Second)
+		now = now.Add(time.Second)
+	}
+
+	if _, ok := cache.Cached("one"); ok {
+		t.Error("Expected the oldest query to be evicted")
+	}
+	if _, ok := cache.Cached("three"); !ok {
+		t.Error("Expected the newest query to be cached")
+	}
+}
+
+func TestSearchCacheTimeoutKeepsSearching(t *testing.T) {
+	var calls atomic.Int32
+	cache := NewSearchCache(context.Background(), countingSearch(&calls, 50*time.Millisecond), time.Minute, 10)
+
+	if _, err := cache.Search(context.Background(), "slow", time.Millisecond); !errors.Is(err, ErrSearchTimeout) {
+		t.Fatalf("Expected ErrSearchTimeout, got %v", err)
+	}
+
+	// The search that timed out is still shared rather than started again
+	results, err := cache.Search(context.Background(), "slow", time.Second)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected results after waiting, got %v, %v", results, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 search, got %d", calls.Load())
+	}
+}
+
+func TestSearchCacheSharesConcurrentSearches(t *testing.T) {
+	var calls atomic.Int32
+	cache := NewSearchCache(context.Background(), countingSearch(&calls, 20*time.Millisecond), time.Minute, 10)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			cache.Search(context.Background(), "popular", time.Second)
+		})
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected concurrent searches to share 1 call, got %d", calls.Load())
+	}
+}
+
+func TestSearchCacheDoesNotCacheErrors(t *testing.T) {
+	var calls atomic.Int32
+	cache := NewSearchCache(context.Background(), func(context.Context, string) ([]YoutubeResult, error) {
+		calls.Add(1)
+		return nil, errors.New("yt-dlp failed")
+	}, time.Minute, 10)
+
+	cache.Search(context.Background(), "broken", time.Second)
+	cache.Search(context.Background(), "broken", time.Second)
+
+	if calls.Load() != 2 {
+		t.Errorf("Expected failed searches to be retried, got %d searches", calls.Load())
+	}
+}
+
+func TestSearchCacheCancelKeepsSearching(t *testing.T) {
+	var calls atomic.Int32
+	cache := NewSearchCache(context.Background(), countingSearch(&calls, 50*time.Millisecond), time.Minute, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cache.Search(ctx, "slow", time.Second); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	// The search carries on for whoever asks next
+	results, err := cache.Search(context.Background(), "slow", time.Second)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected results after waiting, got %v, %v", results, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 search, got %d", calls.Load())
+	}
+}
+
+func TestSearchCacheShutdownEndsSearches(t *testing.T) {
+	appCtx, shutdown := context.WithCancel(context.Background())
+	ended := make(chan struct{})
+	cache := NewSearchCache(appCtx, func(ctx context.Context, query string) ([]YoutubeResult, error) {
+		<-ctx.Done()
+		close(ended)
+		return nil, ctx.Err()
+	}, time.Minute, 10)
+
+	cache.Search(context.Background(), "slow", time.Millisecond)
+	shutdown()
+
+	select {
+	case <-ended:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the shared search to end on shutdown")
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// WorkDirSweepInterval is how often leftover yt-dlp working directories are looked for
+	WorkDirSweepInterval = 10 * time.Minute
+	// workDirMaxAge is how old an unused working directory must be before a sweep removes it
+	workDirMaxAge = 30 * time.Minute
+	// ytdlpWaitDelay is how long to wait for output once yt-dlp is killed, in case
+	// something it started is still holding the pipes open
+	ytdlpWaitDelay = 5 * time.Second
+)
+
+var (
+	// ytdlpPath is the yt-dlp executable, tests point it at a stand-in
+	ytdlpPath = "yt-dlp"
+
+	workDirMutex sync.Mutex
+	// workRoot holds a directory for each yt-dlp run, so runs for different
+	// servers never share fragments or clean up each other's files
+	workRoot = filepath.Join(os.TempDir(), "beatgopher-ytdlp")
+	// activeWorkDirs are the directories yt-dlp is still using, sweeps leave them alone
+	activeWorkDirs = make(map[string]bool)
+)
+
+// SetWorkDir sets where yt-dlp's working directories are made and removes any
+// left behind by a previous run that crashed.
+func SetWorkDir(root string) error {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return fmt.Errorf("error creating yt-dlp work directory: %w", err)
+	}
+
+	workDirMutex.Lock()
+	workRoot = root
+	workDirMutex.Unlock()
+
+	if removed := sweepWorkDirs(0); removed > 0 {
+		log.Printf("Removed %d stale yt-dlp work directories", removed)
+	}
+	return nil
+}
+
+// StartWorkDirSweeper periodically removes working directories that were never
+// cleaned up, e.g. when yt-dlp failed to start.
+func StartWorkDirSweeper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if removed := sweepWorkDirs(workDirMaxAge); removed > 0 {
+				log.Printf("Swept %d stale yt-dlp work directories", removed)
+			}
+		}
+	}()
+}
+
+// newWorkDir makes a fresh working directory, with its own cache, for a yt-dlp run.
+// It stays in use until removeWorkDir is called.
+func newWorkDir(prefix string) (string, error) {
+	workDirMutex.Lock()
+	defer workDirMutex.Unlock()
+
+	if err := os.MkdirAll(workRoot, 0o755); err != nil {
+		return "", fmt.Errorf("error creating yt-dlp work directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(workRoot, prefix+"-")
+	if err != nil {
+		return "", fmt.Errorf("error creating yt-dlp work directory: %w", err)
+	}
+	activeWorkDirs[dir] = true
+	return dir, nil
+}
+
+// removeWorkDir deletes a working directory and everything yt-dlp left in it.
+func removeWorkDir(dir string) {
+	if dir == "" {
+		return
+	}
+
+	workDirMutex.Lock()
+	delete(activeWorkDirs, dir)
+	workDirMutex.Unlock()
+
+	if err := os.RemoveAll(dir); err != nil {
+		log.Printf("Error removing yt-dlp work directory %s: %v", dir, err)
+	}
+}
+
+// sweepWorkDirs removes working directories that aren't in use and haven't
+// changed for maxAge. It returns how many were removed.
+func sweepWorkDirs(maxAge time.Duration) int {
+	workDirMutex.Lock()
+	defer workDirMutex.Unlock()
+
+	entries, err := os.ReadDir(workRoot)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Error reading yt-dlp work directory: %v", err)
+		}
+		return 0
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		dir := filepath.Join(workRoot, entry.Name())
+		if !entry.IsDir() || activeWorkDirs[dir] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < maxAge {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("Error removing yt-dlp work directory %s: %v", dir, err)
+			continue
+		}
+		removed++
+	}
+	return removed
+}
+
+// ytdlpCommand builds a yt-dlp command that runs in its own working directory
+// with its own cache, killed if ctx is cancelled. Call cleanup once the command has finished.
+func ytdlpCommand(ctx context.Context, prefix string, args ...string) (cmd *exec.Cmd, cleanup func(), err error) {
+	dir, err := newWorkDir(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args = append([]string{"--cache-dir", filepath.Join(dir, "cache")}, args...)
+	cmd = exec.CommandContext(ctx, ytdlpPath, args...)
+	cmd.Dir = dir
+	cmd.WaitDelay = ytdlpWaitDelay
+	return cmd, func() { removeWorkDir(dir) }, nil
+}
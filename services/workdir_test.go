@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSetWorkDirRemovesStaleDirectories(t *testing.T) {
+	root := t.TempDir()
+	stale := filepath.Join(root, "stream-crashed")
+	if err := os.MkdirAll(filepath.Join(stale, "cache"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetWorkDir(root); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("Expected the stale directory to be removed at startup")
+	}
+}
+
+func TestYtdlpCommandUsesItsOwnDirectory(t *testing.T) {
+	if err := SetWorkDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	first, cleanupFirst, err := ytdlpCommand(context.Background(), "info", "https://example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, cleanupSecond, err := ytdlpCommand(context.Background(), "info", "https://example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cleanupSecond()
+
+	if first.Dir == second.Dir {
+		t.Errorf("Expected separate directories, both got %s", first.Dir)
+	}
+	if first.Args[1] != "--cache-dir" || first.Args[2] != filepath.Join(first.Dir, "cache") {
+		t.Errorf("Expected a cache directory inside the working directory, got %v", first.Args)
+	}
+
+	cleanupFirst()
+	if _, err := os.Stat(first.Dir); !os.IsNotExist(err) {
+		t.Error("Expected cleanup to remove the directory")
+	}
+	if _, err := os.Stat(second.Dir); err != nil {
+		t.Error("Expected the other directory to be left alone")
+	}
+}
+
+func TestSweepWorkDirsSkipsActiveAndRecent(t *testing.T) {
+	root := t.TempDir()
+	if err := SetWorkDir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	active, err := newWorkDir("stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeWorkDir(active)
+
+	leftover := filepath.Join(root, "stream-leftover")
+	if err := os.Mkdir(leftover, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Too recent to be swept yet
+	if removed := sweepWorkDirs(time.Hour); removed != 0 {
+		t.Errorf("Expected nothing swept, removed %d", removed)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, dir := range []string{active, leftover} {
+		if err := os.Chtimes(dir, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if removed := sweepWorkDirs(time.Hour); removed != 1 {
+		t.Errorf("Expected only the leftover directory swept, removed %d", removed)
+	}
+	if _, err := os.Stat(active); err != nil {
+		t.Error("Expected the active directory to be kept")
+	}
+}
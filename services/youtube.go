@@ -1,31 +1,60 @@
 package services
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os/exec"
-	"strings"
+	"time"
 )
 
-// YoutubeResult holds the structured data for a single YouTube video,
-// parsed from the output of the yt-dlp command.
+// YoutubeResult holds the structured data for a single YouTube video, built
+// from yt-dlp's JSON by ytdlpInfo. The tags are how it's saved in queue snapshots.
 type YoutubeResult struct {
-	ID        string `json:"id"`
-	Channel   string `json:"channel"`
-	Title     string `json:"title"`
-	Duration  string `json:"duration_string"`
-	URL       string `json:"webpage_url"`
-	Thumbnail string `json:"thumbnail"`
+	ID       string `json:"id"`
+	Channel  string `json:"channel"`
+	Title    string `json:"title"`
+	Duration string `json:"duration_string"`
+	// Length is the parsed duration, zero when it isn't known such as for live streams
+	Length      time.Duration `json:"length"`
+	URL         string        `json:"webpage_url"`
+	Thumbnail   string        `json:"thumbnail"`
+	IsLive      bool          `json:"is_live"`
+	UploaderURL string        `json:"uploader_url"`
+	ViewCount   int64         `json:"view_count"`
+	Chapters    []Chapter     `json:"chapters,omitempty"`
+}
+
+// lengthOf works out a video's length from yt-dlp's duration in seconds,
+// falling back to the display duration
+func lengthOf(seconds float64, display string) time.Duration {
+	if seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if length, err := ParseDuration(display); err == nil {
+		return length
+	}
+	return 0
+}
+
+// Chapter is a titled section of a video, with times in seconds from the start.
+type Chapter struct {
+	Title     string  `json:"title"`
+	StartTime float64 `json:"start_time"`
+	EndTime   float64 `json:"end_time"`
 }
 
 // GetYoutubeInfo fetches metadata for a single YouTube video URL by calling yt-dlp.
-func GetYoutubeInfo(url string) (YoutubeResult, error) {
+func GetYoutubeInfo(ctx context.Context, url string) (YoutubeResult, error) {
 	result := YoutubeResult{}
 
 	// Create a new slice with the
 	args := buildYtdlpArgs(url)
 
-	cmd := exec.Command("yt-dlp", args...)
+	cmd, cleanup, err := ytdlpCommand(ctx, "info", args...)
+	if err != nil {
+		return result, err
+	}
+	defer cleanup()
 
 	// Capture stdout and stderr
 	output, err := cmd.Output()
@@ -38,18 +67,22 @@ func GetYoutubeInfo(url string) (YoutubeResult, error) {
 		return result, err
 	}
 
-	return parseYoutubeOutput(output)
+	return parseYoutubeJSON(output)
 }
 
 // SearchYoutube performs a search on YouTube using yt-dlp's "ytsearch:" prefix
 // and returns the first video result.
-func SearchYoutube(query string) (YoutubeResult, error) {
+func SearchYoutube(ctx context.Context, query string) (YoutubeResult, error) {
 	result := YoutubeResult{}
 
 	// yt-dlp args with custom output
 	args := buildYtdlpArgs("ytsearch:" + query)
 
-	cmd := exec.Command("yt-dlp", args...)
+	cmd, cleanup, err := ytdlpCommand(ctx, "search", args...)
+	if err != nil {
+		return result, err
+	}
+	defer cleanup()
 	output, err := cmd.Output()
 
 	if err != nil {
@@ -61,16 +94,53 @@ func SearchYoutube(query string) (YoutubeResult, error) {
 		return result, err
 	}
 
-	return parseYoutubeOutput(output)
+	results := parseYoutubeJSONLines(output)
+	if len(results) == 0 {
+		return result, fmt.Errorf("no results found for %q", query)
+	}
+
+	return results[0], nil
+}
+
+// SearchYoutubeResults searches YouTube using yt-dlp's "ytsearchN:" prefix
+// and returns up to limit results so the user can pick the right one.
+func SearchYoutubeResults(ctx context.Context, query string, limit int) ([]YoutubeResult, error) {
+	results := []YoutubeResult{}
+
+	args := buildYtdlpArgs(fmt.Sprintf("ytsearch%d:%s", limit, query))
+
+	cmd, cleanup, err := ytdlpCommand(ctx, "search", args...)
+	if err != nil {
+		return results, err
+	}
+	defer cleanup()
+	output, err := cmd.Output()
+
+	if err != nil {
+		// Print stderr for debugging
+		if ee, ok := err.(*exec.ExitError); ok {
+			fmt.Println("yt-dlp search error output:", string(ee.Stderr))
+		}
+		fmt.Println("Command error:", err)
+		return results, err
+	}
+
+	// yt-dlp prints one line of JSON for each search result.
+	results = parseYoutubeJSONLines(output)
+	if len(results) == 0 {
+		return results, fmt.Errorf("no results found for %q", query)
+	}
+
+	return results, nil
 }
 
 // GetYoutubePlaylistInfo retrieves metadata for multiple videos from a YouTube playlist URL.
 // It can limit the number of videos processed and optionally randomize the playlist order.
-func GetYoutubePlaylistInfo(playlistURL string, total int64, randomizeSongs bool) ([]YoutubeResult, error) {
+func GetYoutubePlaylistInfo(ctx context.Context, playlistURL string, total int64, randomizeSongs bool) ([]YoutubeResult, error) {
 	results := []YoutubeResult{}
-	// yt-dlp args with custom output
+	// -J prints the whole playlist as a single JSON object with the videos in entries
 	args := []string{
-		"--print", "%(id)s|%(channel)s|%(title)s|%(duration_string)s|%(webpage_url)s|%(thumbnail)s",
+		"-J",
 		"--flat-playlist",
 		"--skip-download",
 		playlistURL,
@@ -80,7 +150,12 @@ func GetYoutubePlaylistInfo(playlistURL string, total int64, randomizeSongs bool
 		args = append(args, "--playlist-random")
 	}
 
-	cmd := exec.Command("yt-dlp", args...)
+	cmd, cleanup, err := ytdlpCommand(ctx, "playlist", args...)
+	if err != nil {
+		return results, err
+	}
+	defer cleanup()
+
 	output, err := cmd.Output()
 
 	if err != nil {
@@ -92,58 +167,17 @@ func GetYoutubePlaylistInfo(playlistURL string, total int64, randomizeSongs bool
 		return results, err
 	}
 
-	// yt-dlp returns one line of output for each video in the playlist.
-	lines := strings.Split(string(output), "\n")
-	var i int64 = 1
-	// Process each line, stopping if the desired total is reached.
-	for _, line := range lines {
-		if i > total {
-			break
-		}
-		if line == "" {
-			continue
-		}
-		result, err := parseYoutubeOutput([]byte(line))
-		if err != nil {
-			log.Printf("Failed to parse line: %v", line)
-			i++
-			continue
-		}
-		results = append(results, result)
-		i++
-	}
-
-	return results, nil
-}
-
-// parseYoutubeOutput takes the raw byte output from a yt-dlp command
-// and parses it into a YoutubeResult struct.
-// It expects a single line of text with fields delimited by "|".
-func parseYoutubeOutput(output []byte) (YoutubeResult, error) {
-	result := YoutubeResult{}
-
-	line := strings.TrimSpace(string(output))
-	if line == "" {
-		return result, fmt.Errorf("empty output from yt-dlp")
-	}
-
-	// The output format is defined by the --print argument in the yt-dlp command.
-	// Example: "VIDEO_ID|CHANNEL_NAME|VIDEO_TITLE|DURATION|VIDEO_URL|THUMBNAIL_URL"
-	parts := strings.SplitN(line, "|", 6)
-	if len(parts) < 6 {
-		return result, fmt.Errorf("unexpected output format: %s", line)
+	results, err = parsePlaylistJSON(output)
+	if err != nil {
+		return results, err
 	}
 
-	result = YoutubeResult{
-		ID:        parts[0],
-		Channel:   parts[1],
-		Title:     parts[2],
-		Duration:  parts[3],
-		URL:       parts[4],
-		Thumbnail: parts[5],
+	// Only keep as many videos as were asked for.
+	if int64(len(results)) > total {
+		results = results[:total]
 	}
 
-	return result, nil
+	return results, nil
 }
 
 // buildYtdlpArgs constructs the command-line arguments for yt-dlp for fetching
@@ -162,7 +196,7 @@ func buildYtdlpArgs(target string) []string {
 		"--no-check-certificate",
 		// --geo-bypass: Attempt to bypass geographic restrictions.
 		"--geo-bypass",
-		// --print: Defines a custom output format. We use "|" as a separator.
-		"--print", "%(id)s|%(channel)s|%(title)s|%(duration_string)s|%(webpage_url)s|%(thumbnail)s",
+		// --dump-json: Print the metadata of each video as a line of JSON.
+		"--dump-json",
 	}
 }
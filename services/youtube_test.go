@@ -0,0 +1,138 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// readFixture loads yt-dlp output captured in testdata
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func TestParseYoutubeJSONVideo(t *testing.T) {
+	result, err := parseYoutubeJSON(readFixture(t, "video.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The pipe in the title used to split it into the wrong fields
+	if result.Title != "Rick Astley - Never Gonna Give You Up | Official Music Video" {
+		t.Errorf("Unexpected title: %q", result.Title)
+	}
+	if result.ID != "dQw4w9WgXcQ" || result.Channel != "Rick Astley" {
+		t.Errorf("Unexpected id or channel: %q, %q", result.ID, result.Channel)
+	}
+	if result.Duration != "3:33" || result.Length != 213*time.Second {
+		t.Errorf("Unexpected duration: %q, %v", result.Duration, result.Length)
+	}
+	if result.URL != "https://www.youtube.com/watch?v=dQw4w9WgXcQ" {
+		t.Errorf("Unexpected URL: %q", result.URL)
+	}
+	if result.UploaderURL != "https://www.youtube.com/@RickAstleyYT" {
+		t.Errorf("Unexpected uploader URL: %q", result.UploaderURL)
+	}
+	if result.ViewCount != 1612345678 {
+		t.Errorf("Unexpected view count: %d", result.ViewCount)
+	}
+	if result.IsLive {
+		t.Error("Expected video not to be live")
+	}
+	if len(result.Chapters) != 3 || result.Chapters[2].Title != "Chorus" || result.Chapters[2].StartTime != 43 {
+		t.Errorf("Unexpected chapters: %+v", result.Chapters)
+	}
+}
+
+func TestParseYoutubeJSONLiveStream(t *testing.T) {
+	result, err := parseYoutubeJSON(readFixture(t, "live.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !result.IsLive {
+		t.Error("Expected stream to be live")
+	}
+	// Live streams have no length, which the rest of the bot knows as "NA"
+	if result.Duration != "NA" || result.Length != 0 {
+		t.Errorf("Expected no duration, got %q, %v", result.Duration, result.Length)
+	}
+	if result.Chapters != nil {
+		t.Errorf("Expected no chapters, got %+v", result.Chapters)
+	}
+}
+
+func TestParseYoutubeJSONLinesSearch(t *testing.T) {
+	results := parseYoutubeJSONLines(readFixture(t, "search.jsonl"))
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	first := results[0]
+	if first.Title != "Queen – Bohemian Rhapsody (Official Video Remastered)" || first.Duration != "5:59" {
+		t.Errorf("Unexpected first result: %+v", first)
+	}
+	if first.URL != "https://www.youtube.com/watch?v=fJ9rUzIMcZQ" {
+		t.Errorf("Expected flat entry url to be used, got %q", first.URL)
+	}
+	// The largest thumbnail is listed last
+	if first.Thumbnail != "https://i.ytimg.com/vi/fJ9rUzIMcZQ/hq720.jpg?sqp=-oaymwEcCNAFEJQDSFXyq4qpAw4IARUAAIhCGAFwAcABBg==" {
+		t.Errorf("Unexpected thumbnail: %q", first.Thumbnail)
+	}
+
+	// Without duration_string the duration is formatted from seconds
+	second := results[1]
+	if second.Duration != "1:02:05" || second.Length != time.Hour+2*time.Minute+5*time.Second {
+		t.Errorf("Expected duration 1:02:05, got %q, %v", second.Duration, second.Length)
+	}
+	if second.UploaderURL != "https://www.youtube.com/channel/UCxxxxxxxxxxxxxxxxxxxxxx" {
+		t.Errorf("Expected channel URL fallback, got %q", second.UploaderURL)
+	}
+}
+
+func TestParseYoutubeJSONLinesSkipsBadLines(t *testing.T) {
+	output := []byte("not json\n\n" + `{"id": "abc", "title": "Works"}` + "\n{}\n")
+
+	results := parseYoutubeJSONLines(output)
+	if len(results) != 1 || results[0].ID != "abc" {
+		t.Fatalf("Expected only the valid line, got %+v", results)
+	}
+	if results[0].URL != "https://www.youtube.com/watch?v=abc" || results[0].Thumbnail != "NA" {
+		t.Errorf("Expected defaults for missing fields, got %+v", results[0])
+	}
+}
+
+func TestParsePlaylistJSON(t *testing.T) {
+	results, err := parsePlaylistJSON(readFixture(t, "playlist.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The deleted video has no id and is skipped
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(results))
+	}
+	if results[0].ID != "kJQP7kiw5Fk" || results[0].Duration != "4:42" || results[0].ViewCount != 8600000000 {
+		t.Errorf("Unexpected first entry: %+v", results[0])
+	}
+	if results[1].Title != "Ed Sheeran - Shape of You (Official Music Video)" {
+		t.Errorf("Unexpected second entry: %+v", results[1])
+	}
+}
+
+func TestParseYoutubeJSONErrors(t *testing.T) {
+	for _, output := range []string{"", "   \n", "{not json", `{"title": "no id"}`} {
+		if _, err := parseYoutubeJSON([]byte(output)); err == nil {
+			t.Errorf("Expected an error for %q", output)
+		}
+	}
+	if _, err := parsePlaylistJSON([]byte("[]")); err == nil {
+		t.Error("Expected an error for a playlist that isn't an object")
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// ytdlpInfo is the part of yt-dlp's JSON output we use. Full video info and
+// the flat entries of playlists and searches fill in different fields, so
+// toResult picks whichever is available.
+type ytdlpInfo struct {
+	ID             string  `json:"id"`
+	Title          string  `json:"title"`
+	Channel        string  `json:"channel"`
+	Uploader       string  `json:"uploader"`
+	UploaderURL    string  `json:"uploader_url"`
+	ChannelURL     string  `json:"channel_url"`
+	Duration       float64 `json:"duration"`
+	DurationString string  `json:"duration_string"`
+	WebpageURL     string  `json:"webpage_url"`
+	URL            string  `json:"url"`
+	Thumbnail      string  `json:"thumbnail"`
+	Thumbnails     []struct {
+		URL string `json:"url"`
+	} `json:"thumbnails"`
+	IsLive     bool      `json:"is_live"`
+	LiveStatus string    `json:"live_status"`
+	ViewCount  int64     `json:"view_count"`
+	Chapters   []Chapter `json:"chapters"`
+}
+
+// ytdlpPlaylist is the JSON yt-dlp prints for a playlist with -J.
+type ytdlpPlaylist struct {
+	Entries []ytdlpInfo `json:"entries"`
+}
+
+// parseYoutubeJSON parses the JSON yt-dlp prints for a single video.
+func parseYoutubeJSON(output []byte) (YoutubeResult, error) {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return YoutubeResult{}, fmt.Errorf("empty output from yt-dlp")
+	}
+
+	var info ytdlpInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return YoutubeResult{}, fmt.Errorf("error parsing yt-dlp output: %w", err)
+	}
+	if info.ID == "" {
+		return YoutubeResult{}, fmt.Errorf("yt-dlp output has no video id")
+	}
+
+	return info.toResult(), nil
+}
+
+// parseYoutubeJSONLines parses --dump-json output, which has one video per line.
+// Lines that can't be parsed are logged and skipped.
+func parseYoutubeJSONLines(output []byte) []YoutubeResult {
+	results := []YoutubeResult{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	// Full video info can be far longer than the default line limit
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		result, err := parseYoutubeJSON(line)
+		if err != nil {
+			log.Printf("Failed to parse yt-dlp line: %v", err)
+			continue
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("Error reading yt-dlp output: %v", err)
+	}
+
+	return results
+}
+
+// parsePlaylistJSON parses the JSON yt-dlp prints for a playlist with -J,
+// skipping entries without an id such as deleted videos.
+func parsePlaylistJSON(output []byte) ([]YoutubeResult, error) {
+	results := []YoutubeResult{}
+
+	var playlist ytdlpPlaylist
+	if err := json.Unmarshal(output, &playlist); err != nil {
+		return results, fmt.Errorf("error parsing yt-dlp playlist output: %w", err)
+	}
+
+	for _, entry := range playlist.Entries {
+		if entry.ID == "" {
+			continue
+		}
+		results = append(results, entry.toResult())
+	}
+
+	return results, nil
+}
+
+// toResult converts yt-dlp's info into a YoutubeResult. Missing text fields are
+// set to "NA", which is what yt-dlp's --print format used to give us.
+func (info ytdlpInfo) toResult() YoutubeResult {
+	result := YoutubeResult{
+		ID:          info.ID,
+		Channel:     firstNonEmpty(info.Channel, info.Uploader),
+		Title:       info.Title,
+		Duration:    info.DurationString,
+		URL:         info.WebpageURL,
+		Thumbnail:   info.Thumbnail,
+		IsLive:      info.IsLive || info.LiveStatus == "is_live",
+		UploaderURL: firstNonEmpty(info.UploaderURL, info.ChannelURL),
+		ViewCount:   info.ViewCount,
+		Chapters:    info.Chapters,
+	}
+
+	result.Length = lengthOf(info.Duration, info.DurationString)
+
+	// Flat playlist and search entries only have the raw duration and url
+	if result.Duration == "" && result.Length > 0 {
+		result.Duration = FormatDuration(result.Length)
+	}
+	if result.URL == "" {
+		result.URL = info.URL
+	}
+	if result.URL == "" {
+		result.URL = "https://www.youtube.com/watch?v=" + info.ID
+	}
+	if result.Thumbnail == "" && len(info.Thumbnails) > 0 {
+		// yt-dlp lists thumbnails from smallest to largest
+		result.Thumbnail = info.Thumbnails[len(info.Thumbnails)-1].URL
+	}
+
+	result.Channel = firstNonEmpty(result.Channel, "NA")
+	result.Duration = firstNonEmpty(result.Duration, "NA")
+	result.Thumbnail = firstNonEmpty(result.Thumbnail, "NA")
+
+	return result
+}
+
+// firstNonEmpty returns the first of values that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
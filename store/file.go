@@ -0,0 +1,64 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeJSONFile writes v as JSON to a temporary file in dir and renames it into
+// place as name, so a crash mid-write never leaves a half written file behind.
+func writeJSONFile(dir string, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %w", name, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing %s: %w", name, err)
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+// readJSONFile decodes the JSON file at path into v, returning false if it doesn't exist.
+func readJSONFile(path string, v any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error reading %s: %w", filepath.Base(path), err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("error decoding %s: %w", filepath.Base(path), err)
+	}
+	return true, nil
+}
+
+// removeFile deletes the file at path, it's not an error if there isn't one.
+func removeFile(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error deleting %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// guildFileName is the name of the file a guild's data is kept in
+func guildFileName(guildID string) string {
+	return filepath.Base(guildID) + ".json"
+}
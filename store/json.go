@@ -0,0 +1,103 @@
+package store
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// JSONFileStore stores each guild's snapshot as a JSON file in a directory.
+type JSONFileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewJSONFileStore creates a store that keeps its files in dir, creating it if needed.
+func NewJSONFileStore(dir string) (*JSONFileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating queue store directory: %w", err)
+	}
+
+	return &JSONFileStore{
+		dir: dir,
+		mu:  sync.Mutex{},
+	}, nil
+}
+
+// Save writes the snapshot to a temporary file and renames it into place,
+// so a crash mid-write never leaves a half written snapshot behind.
+func (js *JSONFileStore) Save(snapshot *GuildSnapshot) error {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	if err := writeJSONFile(js.dir, guildFileName(snapshot.GuildID), snapshot); err != nil {
+		return fmt.Errorf("error saving snapshot for guild %s: %w", snapshot.GuildID, err)
+	}
+	return nil
+}
+
+// Load returns the snapshot for a guild, or nil if there isn't one.
+func (js *JSONFileStore) Load(guildID string) (*GuildSnapshot, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	return js.readFile(js.path(guildID))
+}
+
+// LoadAll returns every stored snapshot, skipping any that can't be read.
+func (js *JSONFileStore) LoadAll() ([]*GuildSnapshot, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	entries, err := os.ReadDir(js.dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading queue store directory: %w", err)
+	}
+
+	snapshots := []*GuildSnapshot{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		snapshot, err := js.readFile(filepath.Join(js.dir, entry.Name()))
+		if err != nil {
+			log.Printf("Skipping unreadable snapshot %s: %v", entry.Name(), err)
+			continue
+		}
+		if snapshot != nil {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	return snapshots, nil
+}
+
+// Delete removes the snapshot for a guild, it's not an error if there isn't one.
+func (js *JSONFileStore) Delete(guildID string) error {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	return removeFile(js.path(guildID))
+}
+
+// readFile decodes a snapshot file, returning nil if it doesn't exist
+func (js *JSONFileStore) readFile(path string) (*GuildSnapshot, error) {
+	snapshot := &GuildSnapshot{}
+	found, err := readJSONFile(path, snapshot)
+	if err != nil || !found {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// path returns the file a guild's snapshot is stored in
+func (js *JSONFileStore) path(guildID string) string {
+	return filepath.Join(js.dir, guildFileName(guildID))
+}
+
+// Verify that JSONFileStore implements QueueStoreInterface at compile time
+var _ QueueStoreInterface = (*JSONFileStore)(nil)
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coreyo-git/beatgopher/queue"
+	"github.com/coreyo-git/beatgopher/services"
+)
+
+func newTestSnapshot(guildID string) *GuildSnapshot {
+	return &GuildSnapshot{
+		GuildID:        guildID,
+		TextChannelID:  "text-" + guildID,
+		VoiceChannelID: "voice-" + guildID,
+		Current: queue.NewTrack(&services.YoutubeResult{
+			ID:       "current",
+			Title:    "Current Song",
+			Duration: "3:30",
+			Length:   210 * time.Second,
+			URL:      "https://youtube.com/watch?v=current",
+		}, "user1", "User 1"),
+		Position: 95 * time.Second,
+		Queue: []*queue.Track{
+			queue.NewTrack(&services.YoutubeResult{ID: "next", Title: "Next | Song"}, "user2", "User 2"),
+		},
+		SavedAt: time.Now(),
+	}
+}
+
+func TestJSONFileStoreRoundTrip(t *testing.T) {
+	js, err := NewJSONFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error creating store: %v", err)
+	}
+
+	snapshot := newTestSnapshot("guild1")
+	if err := js.Save(snapshot); err != nil {
+		t.Fatalf("Unexpected error saving snapshot: %v", err)
+	}
+
+	loaded, err := js.Load("guild1")
+	if err != nil {
+		t.Fatalf("Unexpected error loading snapshot: %v", err)
+	}
+	if loaded == nil {
+		t.Fatal("Expected a snapshot, got nil")
+	}
+
+	if loaded.VoiceChannelID != snapshot.VoiceChannelID || loaded.TextChannelID != snapshot.TextChannelID {
+		t.Errorf("Expected channels %s/%s, got %s/%s", snapshot.VoiceChannelID, snapshot.TextChannelID, loaded.VoiceChannelID, loaded.TextChannelID)
+	}
+	if loaded.Position != snapshot.Position {
+		t.Errorf("Expected position %v, got %v", snapshot.Position, loaded.Position)
+	}
+	if loaded.Current == nil || loaded.Current.ID != "current" || loaded.Current.RequesterName != "User 1" || loaded.Current.Length != 210*time.Second {
+		t.Errorf("Expected current track to round trip, got %+v", loaded.Current)
+	}
+	if len(loaded.Queue) != 1 || loaded.Queue[0].Title != "Next | Song" || loaded.Queue[0].RequesterID != "user2" {
+		t.Errorf("Expected queue to round trip, got %+v", loaded.Queue)
+	}
+}
+
+func TestJSONFileStoreLoadMissing(t *testing.T) {
+	js, _ := NewJSONFileStore(t.TempDir())
+
+	snapshot, err := js.Load("missing")
+	if err != nil {
+		t.Errorf("Expected no error loading a missing snapshot, got %v", err)
+	}
+	if snapshot != nil {
+		t.Errorf("Expected nil for a missing snapshot, got %+v", snapshot)
+	}
+
+	// Deleting a missing snapshot is fine too
+	if err := js.Delete("missing"); err != nil {
+		t.Errorf("Expected no error deleting a missing snapshot, got %v", err)
+	}
+}
+
+func TestJSONFileStoreLoadAllAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	js, _ := NewJSONFileStore(dir)
+
+	js.Save(newTestSnapshot("guild1"))
+	js.Save(newTestSnapshot("guild2"))
+
+	// Corrupt files are skipped rather than failing the whole load
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{not json"), 0o644)
+
+	snapshots, err := js.LoadAll()
+	if err != nil {
+		t.Fatalf("Unexpected error loading snapshots: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Errorf("Expected 2 snapshots, got %d", len(snapshots))
+	}
+
+	if err := js.Delete("guild1"); err != nil {
+		t.Errorf("Unexpected error deleting snapshot: %v", err)
+	}
+
+	snapshots, _ = js.LoadAll()
+	if len(snapshots) != 1 || snapshots[0].GuildID != "guild2" {
+		t.Errorf("Expected only guild2 to remain, got %d snapshots", len(snapshots))
+	}
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// GuildSettings holds a guild's preferences, changed by admins with /settings.
+type GuildSettings struct {
+	// DefaultVolume is the volume the player starts at, as a percentage
+	DefaultVolume int `json:"default_volume"`
+	// DJRoleID is the role allowed to control playback, empty lets everyone
+	DJRoleID string `json:"dj_role_id"`
+	// AnnounceChannelID is where songs are announced, empty uses the channel the bot was first used in
+	AnnounceChannelID string `json:"announce_channel_id"`
+	// IdleTimeout is how long the bot waits with nothing to do before leaving, zero stays forever
+	IdleTimeout time.Duration `json:"idle_timeout"`
+	// Limits on what can be queued, zero turns a limit off
+	MaxTrackLength   time.Duration `json:"max_track_length"`
+	MaxQueueSize     int           `json:"max_queue_size"`
+	MaxTracksPerUser int           `json:"max_tracks_per_user"`
+	// LoopDefault is the loop mode the player starts in: off, track or queue
+	LoopDefault string `json:"loop_default"`
+	// VoteSkipPercent is the share of listeners who must vote to skip a song
+	VoteSkipPercent int `json:"vote_skip_percent"`
+}
+
+// SettingsStoreInterface defines the contract for persisting guild settings
+type SettingsStoreInterface interface {
+	// Load returns the settings for a guild, or nil if they've never been changed
+	Load(guildID string) (*GuildSettings, error)
+
+	// Save stores the settings, replacing any previous ones for the guild
+	Save(guildID string, settings *GuildSettings) error
+
+	// Delete removes the settings for a guild so it goes back to the defaults
+	Delete(guildID string) error
+}
+
+// JSONSettingsStore stores each guild's settings as a JSON file in a directory.
+type JSONSettingsStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewJSONSettingsStore creates a store that keeps its files in dir, creating it if needed.
+func NewJSONSettingsStore(dir string) (*JSONSettingsStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating settings store directory: %w", err)
+	}
+
+	return &JSONSettingsStore{
+		dir: dir,
+		mu:  sync.Mutex{},
+	}, nil
+}
+
+// Load returns the settings for a guild, or nil if they've never been saved.
+func (ss *JSONSettingsStore) Load(guildID string) (*GuildSettings, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	settings := &GuildSettings{}
+	found, err := readJSONFile(filepath.Join(ss.dir, guildFileName(guildID)), settings)
+	if err != nil || !found {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Save writes the settings for a guild, replacing the file atomically.
+func (ss *JSONSettingsStore) Save(guildID string, settings *GuildSettings) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if err := writeJSONFile(ss.dir, guildFileName(guildID), settings); err != nil {
+		return fmt.Errorf("error saving settings for guild %s: %w", guildID, err)
+	}
+	return nil
+}
+
+// Delete removes the settings for a guild, it's not an error if there aren't any.
+func (ss *JSONSettingsStore) Delete(guildID string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	return removeFile(filepath.Join(ss.dir, guildFileName(guildID)))
+}
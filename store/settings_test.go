@@ -0,0 +1,49 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJSONSettingsStoreRoundTrip(t *testing.T) {
+	ss, err := NewJSONSettingsStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error creating store: %v", err)
+	}
+
+	if settings, err := ss.Load("guild1"); err != nil || settings != nil {
+		t.Fatalf("Expected no settings before saving, got %+v, %v", settings, err)
+	}
+
+	settings := &GuildSettings{
+		DefaultVolume:     80,
+		DJRoleID:          "role1",
+		AnnounceChannelID: "channel1",
+		IdleTimeout:       10 * time.Minute,
+		MaxTrackLength:    time.Hour,
+		MaxQueueSize:      100,
+		MaxTracksPerUser:  5,
+		LoopDefault:       "queue",
+	}
+	if err := ss.Save("guild1", settings); err != nil {
+		t.Fatalf("Unexpected error saving settings: %v", err)
+	}
+
+	loaded, err := ss.Load("guild1")
+	if err != nil || loaded == nil {
+		t.Fatalf("Unexpected error loading settings: %v", err)
+	}
+	if *loaded != *settings {
+		t.Errorf("Expected %+v, got %+v", settings, loaded)
+	}
+
+	if err := ss.Delete("guild1"); err != nil {
+		t.Fatalf("Unexpected error deleting settings: %v", err)
+	}
+	if loaded, _ := ss.Load("guild1"); loaded != nil {
+		t.Errorf("Expected settings to be gone after delete, got %+v", loaded)
+	}
+	if err := ss.Delete("guild1"); err != nil {
+		t.Errorf("Expected no error deleting missing settings, got %v", err)
+	}
+}
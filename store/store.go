@@ -0,0 +1,38 @@
+package store
+
+import (
+	"time"
+
+	"github.com/coreyo-git/beatgopher/queue"
+)
+
+// GuildSnapshot holds everything needed to pick a guild's playback back up after a restart.
+type GuildSnapshot struct {
+	GuildID        string         `json:"guild_id"`
+	TextChannelID  string         `json:"text_channel_id"`
+	VoiceChannelID string         `json:"voice_channel_id"`
+	Current        *queue.Track   `json:"current,omitempty"`
+	Position       time.Duration  `json:"position"`
+	Queue          []*queue.Track `json:"queue"`
+	SavedAt        time.Time      `json:"saved_at"`
+}
+
+// IsEmpty returns true if there is nothing in the snapshot worth resuming
+func (gs *GuildSnapshot) IsEmpty() bool {
+	return gs.Current == nil && len(gs.Queue) == 0
+}
+
+// QueueStoreInterface defines the contract for persisting guild queues between restarts
+type QueueStoreInterface interface {
+	// Save stores the snapshot, replacing any previous one for the guild
+	Save(snapshot *GuildSnapshot) error
+
+	// Load returns the snapshot for a guild, or nil if there isn't one
+	Load(guildID string) (*GuildSnapshot, error)
+
+	// LoadAll returns every stored snapshot
+	LoadAll() ([]*GuildSnapshot, error)
+
+	// Delete removes the snapshot for a guild
+	Delete(guildID string) error
+}